@@ -0,0 +1,73 @@
+package main
+
+import (
+	"flag"
+	"os"
+
+	"git-com/commit"
+	"git-com/history"
+	"git-com/output"
+)
+
+// runChangelog implements `git com changelog [range]`.
+// It groups the commits in the range by an element's value and prints
+// them as Markdown, JSON, or through a template.
+func runChangelog(args []string) {
+	fs := flag.NewFlagSet("changelog", flag.ExitOnError)
+	groupBy := fs.String("group-by", "", "Group entries by the value of `ELEMENT` (default: the first select element)")
+	format := fs.String("format", "markdown", "Output `FORMAT`: markdown or json")
+	templatePath := fs.String("template", "", "Render the changelog with the Go text/template in `FILE`")
+	fs.Usage = func() {
+		output.PrintToStderr("Usage: git com changelog [flags] [range]\n\n[range] is read like git log reads it, e.g. v1.2.0..HEAD. It defaults to all of HEAD's history.\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() > 1 {
+		fs.Usage()
+		os.Exit(64)
+	}
+	if *format != "markdown" && *format != "json" {
+		output.PrintError("Unknown format " + *format + ". Use markdown or json.")
+		os.Exit(64)
+	}
+
+	cfg := loadConfigOrExit()
+
+	if *groupBy == "" {
+		*groupBy = history.DefaultGroupBy(cfg)
+		if *groupBy == "" {
+			output.PrintError("There's no select element to group by. Choose an element with --group-by.")
+			os.Exit(64)
+		}
+	}
+
+	commits, err := commit.LogRange(fs.Arg(0), commit.LogFilter{})
+	if err != nil {
+		output.PrintError(err.Error())
+		os.Exit(1)
+	}
+
+	changelog, err := history.BuildChangelog(cfg, commits, *groupBy)
+	if err != nil {
+		output.PrintError(err.Error())
+		os.Exit(1)
+	}
+
+	switch {
+	case *templatePath != "":
+		text, readErr := os.ReadFile(*templatePath)
+		if readErr != nil {
+			output.PrintError("Error reading template: " + readErr.Error())
+			os.Exit(1)
+		}
+		err = changelog.WriteTemplate(os.Stdout, string(text))
+	case *format == "json":
+		err = changelog.WriteJSON(os.Stdout)
+	default:
+		err = changelog.WriteMarkdown(os.Stdout)
+	}
+	if err != nil {
+		output.PrintError("Error writing changelog: " + err.Error())
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"errors"
+	"os"
+
+	"git-com/config"
+	"git-com/output"
+)
+
+// runConfigCommand implements `git com config <subcommand>`
+func runConfigCommand(args []string) {
+	if len(args) == 0 {
+		output.PrintError("Usage: git com config check|schema")
+		os.Exit(64)
+	}
+
+	switch args[0] {
+	case "check":
+		runConfigCheck()
+	case "schema":
+		runConfigSchema()
+	default:
+		output.PrintError("Unknown config command: " + args[0])
+		os.Exit(64)
+	}
+}
+
+// runConfigCheck validates the config file without prompting for anything.
+// Exits 1 if there are any problems.
+func runConfigCheck() {
+	cfg := loadConfigOrExit()
+	problems := config.CheckConfig(cfg)
+	for _, problem := range problems {
+		output.PrintError(problem.Error())
+	}
+	if len(problems) > 0 {
+		os.Exit(1)
+	}
+	output.Print(cfg.FilePath + ": OK")
+}
+
+// runConfigSchema prints the JSON Schema for .git-com.yaml to stdout
+func runConfigSchema() {
+	schema, err := config.SchemaJSON()
+	if err != nil {
+		output.PrintError("Error generating schema: " + err.Error())
+		os.Exit(1)
+	}
+	output.Print(string(schema))
+}
+
+// loads the config from the git root.
+// prints an error and exits if there was a problem
+func loadConfigOrExit() *config.Config {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		if errors.Is(err, config.ErrConfigNotFound) {
+			output.PrintError("Config file .git-com.yaml not found in git repository root")
+		} else if errors.Is(err, config.ErrNotInGitRepo) {
+			output.PrintError("Not in a git repository")
+		} else {
+			output.PrintError("Error loading config: " + err.Error())
+		}
+		os.Exit(1)
+	}
+	return cfg
+}
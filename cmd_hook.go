@@ -0,0 +1,172 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"os/exec"
+
+	"git-com/commit"
+	"git-com/config"
+	"git-com/hook"
+	"git-com/output"
+	"git-com/prompt"
+)
+
+// runHook implements `git com hook <message-file> [source] [sha]`.
+// git runs it as the prepare-commit-msg hook, or, with --editor,
+// as the editor for commit messages.
+func runHook(args []string) {
+	fs := flag.NewFlagSet("hook", flag.ExitOnError)
+	editorMode := fs.Bool("editor", false, "Run as git's editor instead of as a hook")
+	fs.Parse(args)
+
+	if fs.NArg() < 1 {
+		output.PrintError("Usage: git com hook [--editor] <message-file> [source] [sha]")
+		os.Exit(64)
+	}
+	path := fs.Arg(0)
+	source := fs.Arg(1)
+
+	if *editorMode {
+		// rebase todo lists, tag messages, merges, etc. go to a real editor
+		if !hook.IsCommitMessage(path) || hook.MergeInProgress() {
+			runRealEditor(path)
+			return
+		}
+	} else if !hook.ShouldPrompt(source) {
+		return
+	}
+
+	file, err := hook.ReadMessageFile(path)
+	if err != nil {
+		output.PrintError("Error reading commit message: " + err.Error())
+		os.Exit(1)
+	}
+	if *editorMode && file.HasMessage() {
+		// e.g. --amend, or a message our hook already wrote
+		runRealEditor(path)
+		return
+	}
+
+	if !hook.HasTerminal() {
+		if *editorMode {
+			output.PrintError("git com needs a terminal to prompt for the commit message")
+			os.Exit(1)
+		}
+		// leave the message to whatever is committing (e.g. an IDE)
+		return
+	}
+
+	cfg, err := config.LoadConfig()
+	if errors.Is(err, config.ErrConfigNotFound) {
+		// the hook may be shared (core.hooksPath) with repos that don't use git-com
+		if *editorMode {
+			runRealEditor(path)
+		}
+		return
+	}
+	if err != nil {
+		output.PrintError("Error loading config: " + err.Error())
+		os.Exit(1)
+	}
+	if !config.ValidateConfig(cfg) {
+		os.Exit(1)
+	}
+
+	// the diff only helps the prompts, so they go without it if there's a problem
+	_, _ = provideStagedDiff()
+	result, err := prompt.ProcessElements(cfg, nil)
+	if err != nil {
+		if !errors.Is(err, prompt.ErrUserAborted) {
+			output.PrintError("Error processing input: " + err.Error())
+		}
+		// a non-zero exit makes git abort the commit
+		os.Exit(1)
+	}
+
+	// as a hook git opens the editor afterwards, so only confirm as the editor.
+	// Leaving the message empty makes git abort the commit.
+	if *editorMode {
+		showPreview(result)
+		performFinalConfirmation("Is this good?")
+	}
+
+	if err := file.Write(commit.BuildCommitMessage(result.Title, result.Body)); err != nil {
+		output.PrintError("Error writing commit message: " + err.Error())
+		os.Exit(1)
+	}
+	// git makes the commit from here
+	rememberCoAuthors(result)
+}
+
+// opens path in the user's real editor and exits with its status
+func runRealEditor(path string) {
+	if err := hook.RunEditor(path); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.ExitCode())
+		}
+		output.PrintError("Error running editor: " + err.Error())
+		os.Exit(1)
+	}
+}
+
+// runInstallHook implements `git com install-hook`
+func runInstallHook(args []string) {
+	fs := flag.NewFlagSet("install-hook", flag.ExitOnError)
+	editor := fs.Bool("editor", false, "Set git's editor to git-com instead of installing a prepare-commit-msg hook")
+	force := fs.Bool("force", false, "Replace an existing prepare-commit-msg hook")
+	fs.Parse(args)
+
+	if *editor {
+		if err := hook.InstallEditor(); err != nil {
+			output.PrintError("Error setting core.editor: " + err.Error())
+			os.Exit(1)
+		}
+		output.Print("Set core.editor to \"" + hook.EditorCommand + "\" for this repository.")
+		return
+	}
+
+	path, err := hook.Install(*force)
+	if errors.Is(err, hook.ErrHookExists) {
+		output.PrintError(path + " already exists. Use --force to replace it.")
+		os.Exit(1)
+	}
+	if err != nil {
+		output.PrintError("Error installing hook: " + err.Error())
+		os.Exit(1)
+	}
+	output.Print("Installed " + path)
+}
+
+// runUninstallHook implements `git com uninstall-hook`.
+// It removes both the hook and the editor setting, if they're ours.
+func runUninstallHook(args []string) {
+	fs := flag.NewFlagSet("uninstall-hook", flag.ExitOnError)
+	fs.Parse(args)
+
+	removedHook, err := hook.Uninstall()
+	if errors.Is(err, hook.ErrHookExists) {
+		output.PrintWarningToStderr("Leaving the prepare-commit-msg hook alone; git-com didn't install it.")
+	} else if err != nil {
+		output.PrintError("Error removing hook: " + err.Error())
+		os.Exit(1)
+	}
+
+	removedEditor, err := hook.UninstallEditor()
+	if err != nil {
+		output.PrintError("Error unsetting core.editor: " + err.Error())
+		os.Exit(1)
+	}
+
+	if removedHook {
+		output.Print("Removed the prepare-commit-msg hook.")
+	}
+	if removedEditor {
+		output.Print("Unset core.editor.")
+	}
+	if !removedHook && !removedEditor {
+		output.Print("git-com wasn't installed as a hook or editor.")
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"os"
+
+	"git-com/config"
+	"git-com/output"
+	"git-com/setup"
+	"git-com/tui"
+)
+
+// runInit implements `git com init`
+func runInit(args []string) {
+	fs := flag.NewFlagSet("init", flag.ExitOnError)
+	force := fs.Bool("force", false, "Overwrite an existing config file without asking")
+	fs.Parse(args)
+
+	path := defaultConfigPath()
+	if existing, err := config.LoadConfig(); err == nil {
+		if !*force {
+			overwrite, err := tui.Confirm("A git-com config file already exists. Replace it?")
+			if err != nil || !overwrite {
+				os.Exit(1)
+			}
+		}
+		// keep the user's .yml / .yaml choice
+		path = existing.FilePath
+	}
+
+	createConfig(path)
+}
+
+// asks the user if they'd like to generate a config file now
+func offerInit() bool {
+	confirmed, err := tui.Confirm("Would you like to create one now?")
+	return err == nil && confirmed
+}
+
+// returns where a new config file should go.
+// prints an error and exits if we're not in a git repository
+func defaultConfigPath() string {
+	path, err := config.DefaultConfigPath()
+	if err != nil {
+		output.PrintError("Not in a git repository")
+		os.Exit(1)
+	}
+	return path
+}
+
+// runs the interactive config generator and returns the saved config.
+// prints an error and exits if the user aborts or something goes wrong.
+func createConfig(path string) *config.Config {
+	cfg, err := setup.Run(path)
+	if err != nil {
+		if errors.Is(err, tui.ErrAborted) {
+			os.Exit(1)
+		}
+		output.PrintError("Error creating config: " + err.Error())
+		os.Exit(1)
+	}
+	return cfg
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"slices"
+	"strings"
+
+	"git-com/commit"
+	"git-com/history"
+	"git-com/output"
+)
+
+// stringsFlag collects every use of a repeatable flag
+type stringsFlag []string
+
+func (f *stringsFlag) String() string { return strings.Join(*f, ", ") }
+
+func (f *stringsFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+// runLog implements `git com log [flags] [range] [--] [paths...]`.
+// It writes one record per commit, with every element's parsed value,
+// for dashboards and other tools.
+func runLog(args []string) {
+	fs := flag.NewFlagSet("log", flag.ExitOnError)
+	format := fs.String("format", "json", "Output `FORMAT`: json, csv, or ndjson")
+	var authors stringsFlag
+	fs.Var(&authors, "author", "Only commits whose author matches the regular expression `PATTERN` (can be repeated)")
+	fs.Usage = func() {
+		output.PrintToStderr("Usage: git com log [flags] [range] [--] [paths...]\n\n[range] is read like git log reads it, e.g. v1.2.0..HEAD. It defaults to all of HEAD's history.\nPaths limit the commits to those that changed them.\n")
+		fs.PrintDefaults()
+	}
+
+	// like git log, everything after -- is a path
+	var paths []string
+	if i := slices.Index(args, "--"); i >= 0 {
+		args, paths = args[:i], args[i+1:]
+	}
+	fs.Parse(args)
+	if fs.NArg() > 1 {
+		paths = append(fs.Args()[1:], paths...)
+	}
+
+	if !slices.Contains([]string{"json", "csv", "ndjson"}, *format) {
+		output.PrintError("Unknown format " + *format + ". Use json, csv, or ndjson.")
+		os.Exit(64)
+	}
+
+	cfg := loadConfigOrExit()
+
+	commits, err := commit.LogRange(fs.Arg(0), commit.LogFilter{Authors: authors, Paths: paths})
+	if err != nil {
+		output.PrintError(err.Error())
+		os.Exit(1)
+	}
+
+	records := make([]history.Record, len(commits))
+	for i, c := range commits {
+		records[i] = history.NewRecord(cfg, c)
+	}
+
+	switch *format {
+	case "json":
+		err = history.WriteJSON(os.Stdout, records)
+	case "ndjson":
+		err = history.WriteNDJSON(os.Stdout, records)
+	case "csv":
+		err = history.WriteCSV(os.Stdout, cfg, records)
+	}
+	if err != nil {
+		output.PrintError("Error writing log: " + err.Error())
+		os.Exit(1)
+	}
+}
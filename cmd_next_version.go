@@ -0,0 +1,70 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"git-com/commit"
+	"git-com/history"
+	"git-com/output"
+)
+
+// runNextVersion implements `git com next-version`.
+// It prints the version that should follow the latest version tag,
+// based on the version-bump of the options chosen in commits since.
+// The version goes to stdout and the explanation to stderr, so the
+// output can be used directly by release scripts.
+func runNextVersion(args []string) {
+	fs := flag.NewFlagSet("next-version", flag.ExitOnError)
+	fs.Usage = func() {
+		output.PrintToStderr("Usage: git com next-version")
+	}
+	fs.Parse(args)
+	if fs.NArg() != 0 {
+		fs.Usage()
+		os.Exit(64)
+	}
+
+	cfg := loadConfigOrExit()
+
+	tags, err := commit.ReachableTags()
+	if err != nil {
+		output.PrintError("Error reading tags: " + err.Error())
+		os.Exit(1)
+	}
+	_, previousTag, found := history.LatestVersion(tags)
+
+	rng := ""
+	if found {
+		rng = previousTag + ".."
+	}
+	commits, err := commit.LogRange(rng, commit.LogFilter{})
+	if err != nil {
+		output.PrintError(err.Error())
+		os.Exit(1)
+	}
+
+	release, err := history.NextVersion(cfg, previousTag, commits)
+	if err != nil {
+		output.PrintError(err.Error())
+		os.Exit(1)
+	}
+
+	from := release.Previous
+	if !found {
+		from = release.Current.String()
+		output.PrintToStderr("There are no version tags yet, so starting from " + from + ".")
+	}
+	if release.Bump == history.BumpNone {
+		output.PrintWarningToStderr("No commits since " + from + " call for a new version.")
+		output.Print(release.Current.String())
+		return
+	}
+
+	output.PrintToStderr(fmt.Sprintf("%s → %s (%s)", from, release.Next, release.Bump))
+	for _, entry := range release.Reasons {
+		output.PrintToStderr("  " + entry.ShortHash() + " " + entry.Subject)
+	}
+	output.Print(release.Next.String())
+}
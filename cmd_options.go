@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"git-com/output"
+)
+
+// runOptionsCommand implements `git com options <subcommand>`
+func runOptionsCommand(args []string) {
+	if len(args) == 0 || args[0] != "promote" {
+		output.PrintError("Usage: git com options promote")
+		os.Exit(64)
+	}
+	runOptionsPromote()
+}
+
+// runOptionsPromote moves options saved with modifiable-target: local
+// into the shared config file
+func runOptionsPromote() {
+	cfg := loadConfigOrExit()
+
+	promoted, err := cfg.PromoteLocalOptions()
+	if err != nil {
+		output.PrintError("Error promoting options: " + err.Error())
+		os.Exit(1)
+	}
+	if len(promoted) == 0 {
+		output.Print("There are no local options to promote.")
+		return
+	}
+
+	names := make([]string, 0, len(promoted))
+	for name := range promoted {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, option := range promoted[name] {
+			output.Print(fmt.Sprintf("%s: %s", name, option))
+		}
+	}
+	output.Print("\nAdded to " + cfg.FilePath + ". Don't forget to commit it.")
+}
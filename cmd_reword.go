@@ -0,0 +1,99 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"git-com/commit"
+	"git-com/config"
+	"git-com/message"
+	"git-com/output"
+	"git-com/prompt"
+	"git-com/tui"
+
+	"github.com/go-git/go-git/v6/plumbing"
+)
+
+// runReword implements `git com reword <range>`.
+// It prompts for a new message for each commit in the range, oldest
+// first, then rewrites them all at once.
+func runReword(args []string) {
+	fs := flag.NewFlagSet("reword", flag.ExitOnError)
+	fs.Usage = func() {
+		output.PrintToStderr("Usage: git com reword <range>\n\n<range> is a number of commits back from HEAD, or A..HEAD")
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(64)
+	}
+
+	cfg := loadConfigOrExit()
+	if !config.ValidateConfig(cfg) {
+		os.Exit(1)
+	}
+
+	commits, err := commit.CommitRange(fs.Arg(0))
+	if err != nil {
+		output.PrintError(err.Error())
+		os.Exit(1)
+	}
+
+	messages := map[plumbing.Hash]string{}
+	for i, c := range commits {
+		showCommitBeingReworded(c, i+1, len(commits))
+
+		result, err := prompt.ProcessElementsWithDefaults(cfg, message.Parse(cfg, c.Message))
+		if err != nil {
+			if errors.Is(err, prompt.ErrUserAborted) {
+				output.PrintToStderr("Nothing was changed.")
+				os.Exit(0)
+			}
+			output.PrintError("Error processing input: " + err.Error())
+			os.Exit(1)
+		}
+
+		showPreview(result)
+		keep, err := tui.Confirm("Use this message?")
+		if err != nil {
+			output.PrintToStderr("Nothing was changed.")
+			os.Exit(0)
+		}
+		if keep {
+			messages[c.Hash] = commit.BuildCommitMessage(result.Title, result.Body) + "\n"
+		}
+	}
+	tui.SetDiffContext(nil)
+
+	if len(messages) == 0 {
+		output.Print("Nothing was changed.")
+		return
+	}
+
+	backup, err := commit.SaveBackup()
+	if err != nil {
+		output.PrintError("Error saving a backup: " + err.Error())
+		os.Exit(1)
+	}
+	if err := commit.RewriteMessages(messages); err != nil {
+		output.PrintError("Error rewriting commits: " + err.Error())
+		os.Exit(1)
+	}
+
+	output.Print(fmt.Sprintf("Reworded %d of %d commits.", len(messages), len(commits)))
+	output.Print("To undo: git reset --soft " + backup)
+}
+
+// shows the commit's original message and changes above the prompts
+func showCommitBeingReworded(c commit.Summary, n, total int) {
+	summary := fmt.Sprintf("Rewording %d of %d: %s\n\n%s", n, total, c.Hash.String()[:7], strings.TrimSpace(c.Message))
+	files, patch, err := commit.CommitChanges(c.Hash)
+	if err == nil {
+		summary += "\n" + files
+	}
+	tui.SetDiffContext(&tui.DiffContext{Summary: summary, Patch: patch})
+	tui.ShowDiffSummary(true)
+}
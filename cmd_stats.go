@@ -0,0 +1,52 @@
+package main
+
+import (
+	"flag"
+	"os"
+
+	"git-com/commit"
+	"git-com/history"
+	"git-com/output"
+)
+
+// runStats implements `git com stats [range]`.
+// It reports how many commits follow the config, how often each option
+// is chosen, and what the most common problems are.
+func runStats(args []string) {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	format := fs.String("format", "table", "Output `FORMAT`: table or json")
+	var authors stringsFlag
+	fs.Var(&authors, "author", "Only commits whose author matches the regular expression `PATTERN` (can be repeated)")
+	fs.Usage = func() {
+		output.PrintToStderr("Usage: git com stats [flags] [range]\n\n[range] is read like git log reads it, e.g. v1.2.0..HEAD. It defaults to all of HEAD's history.\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() > 1 {
+		fs.Usage()
+		os.Exit(64)
+	}
+	if *format != "table" && *format != "json" {
+		output.PrintError("Unknown format " + *format + ". Use table or json.")
+		os.Exit(64)
+	}
+
+	cfg := loadConfigOrExit()
+
+	commits, err := commit.LogRange(fs.Arg(0), commit.LogFilter{Authors: authors})
+	if err != nil {
+		output.PrintError(err.Error())
+		os.Exit(1)
+	}
+
+	stats := history.BuildStats(cfg, commits)
+	if *format == "json" {
+		err = stats.WriteJSON(os.Stdout)
+	} else {
+		err = stats.WriteTable(os.Stdout)
+	}
+	if err != nil {
+		output.PrintError("Error writing stats: " + err.Error())
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,160 @@
+// Package coauthors finds the people someone is likely to be committing
+// with, for Co-authored-by trailers.
+package coauthors
+
+import (
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	git "github.com/go-git/go-git/v6"
+)
+
+// trailerPrefix starts every co-author trailer
+const trailerPrefix = "Co-authored-by: "
+
+// trailerLine matches a Co-authored-by trailer (case-insensitively, as git does)
+var trailerLine = regexp.MustCompile(`(?im)^co-authored-by:\s*([^<\n]*?)\s*<([^>\n]+)>\s*$`)
+
+// Person is a commit author
+type Person struct {
+	Name  string
+	Email string
+}
+
+// String formats p the way git does: "Name <email>"
+func (p Person) String() string {
+	return p.Name + " <" + p.Email + ">"
+}
+
+// Trailer returns the Co-authored-by trailer for p
+func (p Person) Trailer() string {
+	return trailerPrefix + p.String()
+}
+
+// ParseTrailers returns the people in any Co-authored-by trailers in text
+func ParseTrailers(text string) []Person {
+	var people []Person
+	for _, match := range trailerLine.FindAllStringSubmatch(text, -1) {
+		people = append(people, Person{Name: match[1], Email: match[2]})
+	}
+	return people
+}
+
+// AppendTrailers adds a block of trailers to the end of body. git only
+// reads trailers from the last paragraph, so the block always gets a
+// blank line of its own, whatever whitespace was around it.
+func AppendTrailers(body, trailers string) string {
+	body = strings.TrimRight(body, " \t\n")
+	trailers = strings.TrimLeft(trailers, " \t\n")
+	if body == "" || trailers == "" {
+		return body + trailers
+	}
+	return body + "\n\n" + trailers
+}
+
+// ParsePerson parses "Name <email>"
+func ParsePerson(s string) (Person, bool) {
+	people := ParseTrailers(trailerPrefix + strings.TrimSpace(s))
+	if len(people) == 0 {
+		return Person{}, false
+	}
+	return people[0], true
+}
+
+// Candidates returns possible co-authors: the authors and co-authors of the
+// last depth commits followed by everyone in .mailmap, with identities
+// resolved through .mailmap. People the current user recently paired
+// with come first. The current user isn't included.
+func Candidates(depth int) ([]Person, error) {
+	root, err := exec.Command("git", "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return nil, err
+	}
+	mailmap, err := LoadMailmap(filepath.Join(strings.TrimSpace(string(root)), ".mailmap"))
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := historyPeople(depth)
+	if err != nil {
+		return nil, err
+	}
+
+	user := CurrentUser()
+	seen := map[string]bool{
+		strings.ToLower(user.Email):                  true,
+		strings.ToLower(mailmap.Resolve(user).Email): true,
+	}
+	byEmail := map[string]Person{}
+	var ordered []Person
+	for _, p := range append(history, mailmap.People()...) {
+		p = mailmap.Resolve(p)
+		key := strings.ToLower(p.Email)
+		if p.Email == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		byEmail[key] = p
+		ordered = append(ordered, p)
+	}
+
+	p, err := loadPairings()
+	if err != nil {
+		// forgetting who you paired with isn't worth failing over
+		return ordered, nil
+	}
+	var candidates []Person
+	for _, email := range p.recentFor(user.Email) {
+		if person, ok := byEmail[email]; ok {
+			candidates = append(candidates, person)
+			delete(byEmail, email)
+		}
+	}
+	for _, person := range ordered {
+		if _, ok := byEmail[strings.ToLower(person.Email)]; ok {
+			candidates = append(candidates, person)
+		}
+	}
+	return candidates, nil
+}
+
+// historyPeople returns the authors and co-authors of the last depth
+// commits, most recent first. Duplicates are left in.
+func historyPeople(depth int) ([]Person, error) {
+	repo, err := git.PlainOpen(".")
+	if err != nil {
+		return nil, err
+	}
+	iter, err := repo.Log(&git.LogOptions{})
+	if err != nil {
+		// no commits yet
+		return nil, nil
+	}
+	defer iter.Close()
+
+	var people []Person
+	for i := 0; i < depth; i++ {
+		c, err := iter.Next()
+		if err != nil {
+			break
+		}
+		people = append(people, Person{Name: c.Author.Name, Email: c.Author.Email})
+		people = append(people, ParseTrailers(c.Message)...)
+	}
+	return people, nil
+}
+
+// CurrentUser returns user.name and user.email from git config
+func CurrentUser() Person {
+	return Person{Name: gitConfig("user.name"), Email: gitConfig("user.email")}
+}
+
+func gitConfig(key string) string {
+	output, err := exec.Command("git", "config", "--get", key).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
@@ -0,0 +1,132 @@
+package coauthors
+
+import (
+	"os"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMailmap(t *testing.T) {
+	mailmap, err := parseMailmap(strings.NewReader(`# comment
+Jane Doe <jane@example.com>
+<joe@example.com> <joe@old.example.com>
+Ann Lee <ann@example.com> <alee@laptop.local>
+Bob Roe <bob@example.com> bobby <bob@shared.example.com>
+`))
+	if err != nil {
+		t.Fatalf("parseMailmap() error = %v", err)
+	}
+
+	tests := []struct {
+		in, want Person
+	}{
+		{Person{"jane", "jane@example.com"}, Person{"Jane Doe", "jane@example.com"}},
+		{Person{"Joe", "joe@old.example.com"}, Person{"Joe", "joe@example.com"}},
+		{Person{"ann", "ALEE@laptop.local"}, Person{"Ann Lee", "ann@example.com"}},
+		{Person{"bobby", "bob@shared.example.com"}, Person{"Bob Roe", "bob@example.com"}},
+		{Person{"someone else", "bob@shared.example.com"}, Person{"someone else", "bob@shared.example.com"}},
+		{Person{"Zed", "zed@example.com"}, Person{"Zed", "zed@example.com"}},
+	}
+	for _, tt := range tests {
+		if got := mailmap.Resolve(tt.in); got != tt.want {
+			t.Errorf("Resolve(%v) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+
+	want := []Person{{"Jane Doe", "jane@example.com"}, {"Ann Lee", "ann@example.com"}, {"Bob Roe", "bob@example.com"}}
+	if got := mailmap.People(); !reflect.DeepEqual(got, want) {
+		t.Errorf("People() = %v, want %v", got, want)
+	}
+}
+
+func TestParseTrailers(t *testing.T) {
+	message := "title\n\nbody\n\nCo-authored-by: Jane Doe <jane@example.com>\nco-authored-by: Joe <joe@example.com>\n"
+	want := []Person{{"Jane Doe", "jane@example.com"}, {"Joe", "joe@example.com"}}
+	if got := ParseTrailers(message); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseTrailers() = %v, want %v", got, want)
+	}
+	if got := want[0].Trailer(); got != "Co-authored-by: Jane Doe <jane@example.com>" {
+		t.Errorf("Trailer() = %q", got)
+	}
+}
+
+func TestAppendTrailers(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git isn't installed")
+	}
+	trailers := "Co-authored-by: Jane Doe <jane@example.com>\nCo-authored-by: Joe <joe@example.com>"
+	tests := []struct {
+		name, body, trailers string
+	}{
+		{"no before-string", "Details of the change.", trailers},
+		{"blank line already", "Details of the change.\n\n", "\n\n" + trailers},
+		{"one newline", "Details of the change.\n", "\n" + trailers},
+		{"nothing else in the body", "", "\n\n" + trailers},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := AppendTrailers(tt.body, tt.trailers)
+			cmd := exec.Command("git", "interpret-trailers", "--parse")
+			cmd.Stdin = strings.NewReader("feat: add a thing\n\n" + body + "\n")
+			out, err := cmd.Output()
+			if err != nil {
+				t.Fatalf("git interpret-trailers: %v", err)
+			}
+			if got := strings.TrimSpace(string(out)); got != trailers {
+				t.Errorf("git read trailers %q from %q, want %q", got, body, trailers)
+			}
+		})
+	}
+}
+
+func TestCandidates(t *testing.T) {
+	t.Chdir(t.TempDir())
+	git := func(args ...string) {
+		t.Helper()
+		if err := exec.Command("git", args...).Run(); err != nil {
+			t.Skipf("git %v: %v", args, err)
+		}
+	}
+	git("init", "-q")
+	git("config", "user.name", "Me")
+	git("config", "user.email", "me@example.com")
+	git("commit", "-q", "--allow-empty", "-m", "mine")
+	git("commit", "-q", "--allow-empty", "-m", "ann's", "--author", "ann <alee@laptop.local>")
+	git("commit", "-q", "--allow-empty", "-m", "pairing\n\nCo-authored-by: Cat <cat@example.com>")
+	git("commit", "-q", "--allow-empty", "-m", "bob's", "--author", "Bob <bob@example.com>")
+	if err := os.WriteFile(".mailmap", []byte("Ann Lee <ann@example.com> <alee@laptop.local>\nDee <dee@example.com>\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	candidates, err := Candidates(100)
+	if err != nil {
+		t.Fatalf("Candidates() error = %v", err)
+	}
+	want := "Bob <bob@example.com>, Cat <cat@example.com>, Ann Lee <ann@example.com>, Dee <dee@example.com>"
+	if got := joinPeople(candidates); got != want {
+		t.Errorf("Candidates() = %s, want %s", got, want)
+	}
+
+	if err := RememberPairing("me@example.com", []Person{{"Dee", "dee@example.com"}}); err != nil {
+		t.Fatalf("RememberPairing() error = %v", err)
+	}
+	candidates, _ = Candidates(100)
+	want = "Dee <dee@example.com>, Bob <bob@example.com>, Cat <cat@example.com>, Ann Lee <ann@example.com>"
+	if got := joinPeople(candidates); got != want {
+		t.Errorf("Candidates() after pairing = %s, want %s", got, want)
+	}
+
+	if shallow, _ := Candidates(1); joinPeople(shallow) != "Dee <dee@example.com>, Bob <bob@example.com>, Ann Lee <ann@example.com>" {
+		t.Errorf("Candidates(1) = %s", joinPeople(shallow))
+	}
+}
+
+func joinPeople(people []Person) string {
+	names := make([]string, len(people))
+	for i, p := range people {
+		names[i] = p.String()
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,105 @@
+package coauthors
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// mailmapLine matches the forms a .mailmap line can take:
+//
+//	Proper Name <commit@email>
+//	<proper@email> <commit@email>
+//	Proper Name <proper@email> <commit@email>
+//	Proper Name <proper@email> Commit Name <commit@email>
+var mailmapLine = regexp.MustCompile(`^([^<]*)<([^>]*)>(?:\s*([^<]*)<([^>]*)>)?`)
+
+// mailmapEntry maps a commit identity to a proper one.
+// An empty commitName matches any name with commitEmail.
+type mailmapEntry struct {
+	proper      Person
+	commitName  string
+	commitEmail string
+}
+
+// Mailmap rewrites author identities the way `git log --use-mailmap` does
+type Mailmap struct {
+	entries []mailmapEntry
+}
+
+// LoadMailmap reads the mailmap at path. A missing file is an empty mailmap.
+func LoadMailmap(path string) (*Mailmap, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return &Mailmap{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return parseMailmap(file)
+}
+
+func parseMailmap(r io.Reader) (*Mailmap, error) {
+	mailmap := &Mailmap{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		match := mailmapLine.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil {
+			continue
+		}
+		entry := mailmapEntry{
+			proper:      Person{Name: strings.TrimSpace(match[1]), Email: match[2]},
+			commitEmail: match[2],
+		}
+		if match[4] != "" {
+			// the second address is the one found in commits
+			entry.commitName = strings.TrimSpace(match[3])
+			entry.commitEmail = match[4]
+		}
+		mailmap.entries = append(mailmap.entries, entry)
+	}
+	return mailmap, scanner.Err()
+}
+
+// Resolve returns the proper identity for someone as they appear in a commit
+func (m *Mailmap) Resolve(p Person) Person {
+	var found *mailmapEntry
+	for i, entry := range m.entries {
+		if !strings.EqualFold(entry.commitEmail, p.Email) {
+			continue
+		}
+		if entry.commitName != "" && !strings.EqualFold(entry.commitName, p.Name) {
+			continue
+		}
+		// entries with a name are more specific; later lines win otherwise
+		if found == nil || entry.commitName != "" || found.commitName == "" {
+			found = &m.entries[i]
+		}
+	}
+	if found == nil {
+		return p
+	}
+	resolved := p
+	if found.proper.Name != "" {
+		resolved.Name = found.proper.Name
+	}
+	if found.proper.Email != "" {
+		resolved.Email = found.proper.Email
+	}
+	return resolved
+}
+
+// People returns everyone the mailmap gives a proper name and email
+func (m *Mailmap) People() []Person {
+	var people []Person
+	for _, entry := range m.entries {
+		if entry.proper.Name != "" && entry.proper.Email != "" {
+			people = append(people, entry.proper)
+		}
+	}
+	return people
+}
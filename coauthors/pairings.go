@@ -0,0 +1,93 @@
+package coauthors
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// pairingsFileName is where recent pairings are remembered.
+// It lives in the .git directory so it's never committed.
+const pairingsFileName = "git-com-pairings.yaml"
+
+// pairings maps a user's email to when they last committed with each
+// co-author, keyed by the co-author's email
+type pairings map[string]map[string]time.Time
+
+// pairingsPath returns the path of the pairings file
+func pairingsPath() (string, error) {
+	output, err := exec.Command("git", "rev-parse", "--git-path", pairingsFileName).Output()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Abs(strings.TrimSpace(string(output)))
+}
+
+// loadPairings reads the pairings file. A missing file is not an error.
+func loadPairings() (pairings, error) {
+	path, err := pairingsPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return pairings{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	p := pairings{}
+	if err := yaml.Unmarshal(data, &p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// recentFor returns user's co-author emails, most recent first
+func (p pairings) recentFor(user string) []string {
+	partners := p[strings.ToLower(user)]
+	emails := make([]string, 0, len(partners))
+	for email := range partners {
+		emails = append(emails, email)
+	}
+	sort.Slice(emails, func(i, j int) bool {
+		return partners[emails[i]].After(partners[emails[j]])
+	})
+	return emails
+}
+
+// RememberPairing records that user just committed with coAuthors,
+// so they're listed first next time
+func RememberPairing(user string, coAuthors []Person) error {
+	if user == "" || len(coAuthors) == 0 {
+		return nil
+	}
+	p, err := loadPairings()
+	if err != nil {
+		return err
+	}
+
+	user = strings.ToLower(user)
+	if p[user] == nil {
+		p[user] = map[string]time.Time{}
+	}
+	now := time.Now()
+	for _, person := range coAuthors {
+		p[user][strings.ToLower(person.Email)] = now
+	}
+
+	data, err := yaml.Marshal(p)
+	if err != nil {
+		return err
+	}
+	path, err := pairingsPath()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
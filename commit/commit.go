@@ -1,13 +1,10 @@
 package commit
 
 import (
-	"errors"
 	"os/exec"
 	"strings"
-	"time"
 
 	git "github.com/go-git/go-git/v6"
-	"github.com/go-git/go-git/v6/plumbing/object"
 )
 
 // HasStagedFiles checks if there are any staged files in the repository
@@ -38,7 +35,7 @@ func HasStagedFiles() (bool, error) {
 }
 
 // CreateCommit creates a git commit with the given title and body
-func CreateCommit(title, body string) error {
+func CreateCommit(title, body string, opts Options) error {
 	// Open the repository
 	repo, err := git.PlainOpen(".")
 	if err != nil {
@@ -51,43 +48,30 @@ func CreateCommit(title, body string) error {
 		return err
 	}
 
-	// Get author info from git config (handles includes properly)
-	author, err := getAuthorFromGitConfig()
+	// Work out the author and committer like git does
+	// (this properly handles [include] directives in .gitconfig)
+	author, err := opts.authorSignature()
+	if err != nil {
+		return err
+	}
+	committer, err := committerSignature()
 	if err != nil {
 		return err
 	}
 
 	// Build the commit message
-	message := buildCommitMessage(title, body)
+	message := BuildCommitMessage(title, body)
 
 	// Create the commit
 	_, err = wt.Commit(message, &git.CommitOptions{
-		Author: author,
+		Author:            author,
+		Committer:         committer,
+		AllowEmptyCommits: opts.AllowEmpty,
 	})
 
 	return err
 }
 
-// getAuthorFromGitConfig gets author info using git config command
-// This properly handles [include] directives in .gitconfig
-func getAuthorFromGitConfig() (*object.Signature, error) {
-	name, err := runGitConfig("user.name")
-	if err != nil {
-		return nil, errors.New("author field is required: could not get user.name from git config")
-	}
-
-	email, err := runGitConfig("user.email")
-	if err != nil {
-		return nil, errors.New("author field is required: could not get user.email from git config")
-	}
-
-	return &object.Signature{
-		Name:  name,
-		Email: email,
-		When:  time.Now(),
-	}, nil
-}
-
 // runGitConfig runs git config to get a value
 func runGitConfig(key string) (string, error) {
 	cmd := exec.Command("git", "config", "--get", key)
@@ -98,8 +82,8 @@ func runGitConfig(key string) (string, error) {
 	return strings.TrimSpace(string(output)), nil
 }
 
-// buildCommitMessage constructs the full commit message from title and body
-func buildCommitMessage(title, body string) string {
+// BuildCommitMessage constructs the full commit message from title and body
+func BuildCommitMessage(title, body string) string {
 	title = strings.TrimSpace(title)
 	body = strings.TrimSpace(body)
 
@@ -139,53 +123,52 @@ func GetLastCommitBody() (*string, error) {
 		return nil, err
 	}
 
-	commit, err := repo.CommitObject(head.Hash())
+	return GetCommitBody(head.Hash())
+}
+
+// AmendCommit amends the last commit with a new message.
+// Like `git commit --amend` the original author and author date are kept
+// unless opts says otherwise.
+func AmendCommit(title, body string, opts Options) error {
+	repo, err := git.PlainOpen(".")
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	message := commit.Message
-	// Split message into lines
-	lines := strings.SplitN(message, "\n", 3)
-	if len(lines) < 3 {
-		// No body
-		return nil, nil
+	wt, err := repo.Worktree()
+	if err != nil {
+		return err
 	}
 
-	// The body is everything after the first line, trimmed of leading/trailing whitespace
-	body := strings.TrimSpace(lines[2])
-	if body == "" {
-		return nil, nil
+	head, err := repo.Head()
+	if err != nil {
+		return err
 	}
-
-	return &body, nil
-}
-
-// AmendCommit amends the last commit with a new message
-func AmendCommit(title, body string) error {
-	repo, err := git.PlainOpen(".")
+	headCommit, err := repo.CommitObject(head.Hash())
 	if err != nil {
 		return err
 	}
 
-	wt, err := repo.Worktree()
+	author, err := opts.amendAuthorSignature(headCommit)
 	if err != nil {
 		return err
 	}
-
-	// Get author info from git config
-	author, err := getAuthorFromGitConfig()
+	committer, err := committerSignature()
 	if err != nil {
 		return err
 	}
 
 	// Build the commit message
-	message := buildCommitMessage(title, body)
+	message := BuildCommitMessage(title, body)
 
 	// Create the commit with amend option
 	_, err = wt.Commit(message, &git.CommitOptions{
-		Author: author,
-		Amend:  true,
+		Author:    author,
+		Committer: committer,
+		Amend:     true,
+		// Like git, amending only the message is always allowed,
+		// even when the amended commit doesn't change any files
+		AllowEmptyCommits: true,
 	})
 
 	return err
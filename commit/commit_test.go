@@ -0,0 +1,38 @@
+package commit
+
+import "testing"
+
+func TestCreateCommit_AllowEmpty(t *testing.T) {
+	initGitRepo(t)
+	clearIdentityEnv(t)
+	commitFile(t, "a.txt", "add a")
+
+	if err := CreateCommit("trigger CI", "", Options{}); err == nil {
+		t.Fatal("expected an error creating an empty commit without AllowEmpty")
+	}
+	if err := CreateCommit("trigger CI", "", Options{AllowEmpty: true}); err != nil {
+		t.Fatal(err)
+	}
+	if got := gitOutput(t, "log", "-1", "--format=%s"); got != "trigger CI" {
+		t.Errorf("subject = %q", got)
+	}
+	if got := gitOutput(t, "diff", "--name-only", "HEAD~1", "HEAD"); got != "" {
+		t.Errorf("empty commit changed %q", got)
+	}
+}
+
+func TestAmendCommit_MessageOnly(t *testing.T) {
+	initGitRepo(t)
+	clearIdentityEnv(t)
+	commitFile(t, "a.txt", "add a")
+	if err := CreateCommit("release 1.0", "", Options{AllowEmpty: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := AmendCommit("release 1.0.0", "", Options{}); err != nil {
+		t.Fatal(err)
+	}
+	if got := gitOutput(t, "log", "--format=%s"); got != "release 1.0.0\nadd a" {
+		t.Errorf("log = %q", got)
+	}
+}
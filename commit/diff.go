@@ -0,0 +1,255 @@
+package commit
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	git "github.com/go-git/go-git/v6"
+	"github.com/go-git/go-git/v6/plumbing"
+	"github.com/go-git/go-git/v6/plumbing/filemode"
+	fdiff "github.com/go-git/go-git/v6/plumbing/format/diff"
+	"github.com/go-git/go-git/v6/utils/diff"
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// FileStat is a staged file with the number of lines added and removed
+type FileStat struct {
+	Path    string
+	Added   int
+	Removed int
+	Binary  bool
+}
+
+// String formats the stat like `git diff --stat`, without the bar graph
+func (s FileStat) String() string {
+	if s.Binary {
+		return s.Path + " (binary)"
+	}
+	return fmt.Sprintf("%s +%d -%d", s.Path, s.Added, s.Removed)
+}
+
+// StagedDiff is the difference between the HEAD tree and the index
+type StagedDiff struct {
+	Files   []FileStat
+	patches []fdiff.FilePatch
+}
+
+// Summary returns one line per file
+func (d *StagedDiff) Summary() string {
+	lines := make([]string, len(d.Files))
+	for i, stat := range d.Files {
+		lines[i] = stat.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Patch returns the unified diff, in color if color is true
+func (d *StagedDiff) Patch(color bool) (string, error) {
+	var buf bytes.Buffer
+	encoder := fdiff.NewUnifiedEncoder(&buf, fdiff.DefaultContextLines)
+	if color {
+		encoder.SetColor(fdiff.NewColorConfig())
+	}
+	if err := encoder.Encode(patch(d.patches)); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// GetStagedDiff compares the index to the HEAD tree.
+// Before the first commit everything in the index is new.
+func GetStagedDiff() (*StagedDiff, error) {
+	repo, err := git.PlainOpen(".")
+	if err != nil {
+		return nil, err
+	}
+
+	before := map[string]*diffFile{}
+	if head, err := repo.Head(); err == nil {
+		headCommit, err := repo.CommitObject(head.Hash())
+		if err != nil {
+			return nil, err
+		}
+		tree, err := headCommit.Tree()
+		if err != nil {
+			return nil, err
+		}
+		files := tree.Files()
+		defer files.Close()
+		for {
+			file, err := files.Next()
+			if err != nil {
+				break
+			}
+			before[file.Name] = &diffFile{path: file.Name, hash: file.Hash, mode: file.Mode}
+		}
+	} else if err != plumbing.ErrReferenceNotFound {
+		return nil, err
+	}
+
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return nil, err
+	}
+	after := map[string]*diffFile{}
+	for _, entry := range idx.Entries {
+		after[entry.Name] = &diffFile{path: entry.Name, hash: entry.Hash, mode: entry.Mode}
+	}
+
+	paths := map[string]bool{}
+	for path := range before {
+		paths[path] = true
+	}
+	for path := range after {
+		paths[path] = true
+	}
+
+	result := &StagedDiff{}
+	for _, path := range sortedKeys(paths) {
+		from, to := before[path], after[path]
+		if from != nil && to != nil && from.hash == to.hash && from.mode == to.mode {
+			continue
+		}
+		filePatch, err := newFilePatch(repo, from, to)
+		if err != nil {
+			return nil, err
+		}
+		result.Files = append(result.Files, filePatch.stat(path))
+		result.patches = append(result.patches, filePatch)
+	}
+	return result, nil
+}
+
+// newFilePatch diffs the contents of two versions of a file.
+// from is nil for new files and to is nil for deleted ones.
+func newFilePatch(repo *git.Repository, from, to *diffFile) (*filePatch, error) {
+	fromContent, fromBinary, err := blobContent(repo, from)
+	if err != nil {
+		return nil, err
+	}
+	toContent, toBinary, err := blobContent(repo, to)
+	if err != nil {
+		return nil, err
+	}
+
+	fp := &filePatch{from: from, to: to, binary: fromBinary || toBinary}
+	if fp.binary {
+		return fp, nil
+	}
+	for _, d := range diff.Do(fromContent, toContent) {
+		op := fdiff.Equal
+		switch d.Type {
+		case diffmatchpatch.DiffInsert:
+			op = fdiff.Add
+		case diffmatchpatch.DiffDelete:
+			op = fdiff.Delete
+		}
+		fp.chunks = append(fp.chunks, chunk{content: d.Text, op: op})
+	}
+	return fp, nil
+}
+
+// blobContent reads a file's contents, reporting whether it looks binary
+func blobContent(repo *git.Repository, file *diffFile) (string, bool, error) {
+	if file == nil || file.mode == filemode.Submodule {
+		return "", false, nil
+	}
+	blob, err := repo.BlobObject(file.hash)
+	if err != nil {
+		return "", false, err
+	}
+	reader, err := blob.Reader()
+	if err != nil {
+		return "", false, err
+	}
+	defer reader.Close()
+
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(reader); err != nil {
+		return "", false, err
+	}
+	// git's heuristic: a NUL in the first 8000 bytes means binary
+	head := buf.Bytes()[:min(buf.Len(), 8000)]
+	return buf.String(), bytes.IndexByte(head, 0) >= 0, nil
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// countLines counts lines the way diff does: a trailing
+// line without a newline still counts
+func countLines(s string) int {
+	n := strings.Count(s, "\n")
+	if s != "" && !strings.HasSuffix(s, "\n") {
+		n++
+	}
+	return n
+}
+
+// The types below implement go-git's diff.Patch interfaces
+// so the unified encoder can render the staged changes.
+
+type patch []fdiff.FilePatch
+
+func (p patch) FilePatches() []fdiff.FilePatch { return p }
+func (p patch) Message() string                { return "" }
+
+type filePatch struct {
+	from, to *diffFile
+	chunks   []fdiff.Chunk
+	binary   bool
+}
+
+func (p *filePatch) IsBinary() bool        { return p.binary }
+func (p *filePatch) Chunks() []fdiff.Chunk { return p.chunks }
+func (p *filePatch) Files() (fdiff.File, fdiff.File) {
+	// avoid returning typed nils
+	var from, to fdiff.File
+	if p.from != nil {
+		from = p.from
+	}
+	if p.to != nil {
+		to = p.to
+	}
+	return from, to
+}
+
+// stat counts the added and removed lines
+func (p *filePatch) stat(path string) FileStat {
+	stat := FileStat{Path: path, Binary: p.binary}
+	for _, c := range p.chunks {
+		switch c.Type() {
+		case fdiff.Add:
+			stat.Added += countLines(c.Content())
+		case fdiff.Delete:
+			stat.Removed += countLines(c.Content())
+		}
+	}
+	return stat
+}
+
+type diffFile struct {
+	path string
+	hash plumbing.Hash
+	mode filemode.FileMode
+}
+
+func (f *diffFile) Hash() plumbing.Hash     { return f.hash }
+func (f *diffFile) Mode() filemode.FileMode { return f.mode }
+func (f *diffFile) Path() string            { return f.path }
+
+type chunk struct {
+	content string
+	op      fdiff.Operation
+}
+
+func (c chunk) Content() string       { return c.content }
+func (c chunk) Type() fdiff.Operation { return c.op }
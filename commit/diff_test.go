@@ -0,0 +1,63 @@
+package commit
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestGetStagedDiff(t *testing.T) {
+	initGitRepo(t)
+	writeFile(t, "changed.txt", "one\ntwo\nthree\n")
+	writeFile(t, "removed.txt", "gone\n")
+	if err := exec.Command("git", "add", ".").Run(); err != nil {
+		t.Fatal(err)
+	}
+	if err := exec.Command("git", "commit", "-qm", "initial").Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	writeFile(t, "changed.txt", "one\n2\nthree\nfour\n")
+	os.Remove("removed.txt")
+	writeFile(t, "added.txt", "new")
+	writeFile(t, "unstaged.txt", "not staged\n")
+	if err := StageFiles([]string{"changed.txt", "removed.txt", "added.txt"}); err != nil {
+		t.Fatal(err)
+	}
+
+	diff, err := GetStagedDiff()
+	if err != nil {
+		t.Fatalf("GetStagedDiff() error = %v", err)
+	}
+	want := "added.txt +1 -0\nchanged.txt +2 -1\nremoved.txt +0 -1"
+	if got := diff.Summary(); got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+
+	patch, err := diff.Patch(false)
+	if err != nil {
+		t.Fatalf("Patch() error = %v", err)
+	}
+	for _, expected := range []string{"+++ b/changed.txt", "-two", "+2", "+four", "--- a/removed.txt", "+new"} {
+		if !strings.Contains(patch, expected) {
+			t.Errorf("Patch() is missing %q:\n%s", expected, patch)
+		}
+	}
+}
+
+func TestGetStagedDiff_BeforeFirstCommit(t *testing.T) {
+	initGitRepo(t)
+	writeFile(t, "first.txt", "a\nb\n")
+	if err := StageFiles([]string{"first.txt"}); err != nil {
+		t.Fatal(err)
+	}
+
+	diff, err := GetStagedDiff()
+	if err != nil {
+		t.Fatalf("GetStagedDiff() error = %v", err)
+	}
+	if got := diff.Summary(); got != "first.txt +2 -0" {
+		t.Errorf("Summary() = %q, want %q", got, "first.txt +2 -0")
+	}
+}
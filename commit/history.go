@@ -0,0 +1,305 @@
+package commit
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	git "github.com/go-git/go-git/v6"
+	"github.com/go-git/go-git/v6/plumbing"
+	"github.com/go-git/go-git/v6/plumbing/object"
+)
+
+// Summary identifies a commit by its hash and subject
+type Summary struct {
+	Hash    plumbing.Hash
+	Subject string
+	Message string // the full message
+	Author  object.Signature
+}
+
+// String formats the summary like `git log --oneline`
+func (s Summary) String() string {
+	return s.Hash.String()[:7] + " " + s.Subject
+}
+
+// RecentCommits returns up to limit commits reachable from HEAD, newest first
+func RecentCommits(limit int) ([]Summary, error) {
+	repo, err := git.PlainOpen(".")
+	if err != nil {
+		return nil, err
+	}
+
+	iter, err := repo.Log(&git.LogOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	var commits []Summary
+	for len(commits) < limit {
+		c, err := iter.Next()
+		if err != nil {
+			break
+		}
+		commits = append(commits, summarize(c))
+	}
+	return commits, nil
+}
+
+// ResolveCommit finds the commit that rev (a hash, branch, HEAD~2, etc.) refers to
+func ResolveCommit(rev string) (Summary, error) {
+	repo, err := git.PlainOpen(".")
+	if err != nil {
+		return Summary{}, err
+	}
+
+	hash, err := repo.ResolveRevision(plumbing.Revision(rev))
+	if err != nil {
+		return Summary{}, errors.New("unknown revision " + rev)
+	}
+	c, err := repo.CommitObject(*hash)
+	if err != nil {
+		return Summary{}, errors.New(rev + " is not a commit")
+	}
+	return summarize(c), nil
+}
+
+// GetCommitBody returns the body of the given commit (everything after the 2nd line)
+// Returns nil if there is no body or if the body is empty
+func GetCommitBody(hash plumbing.Hash) (*string, error) {
+	repo, err := git.PlainOpen(".")
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := repo.CommitObject(hash)
+	if err != nil {
+		return nil, err
+	}
+	return messageBody(c.Message), nil
+}
+
+// messageBody returns everything after the title and blank line, or nil if empty
+func messageBody(message string) *string {
+	lines := strings.SplitN(message, "\n", 3)
+	if len(lines) < 3 {
+		return nil
+	}
+	body := strings.TrimSpace(lines[2])
+	if body == "" {
+		return nil
+	}
+	return &body
+}
+
+// summarize returns the hash and message of a commit
+func summarize(c *object.Commit) Summary {
+	subject, _, _ := strings.Cut(c.Message, "\n")
+	return Summary{Hash: c.Hash, Subject: strings.TrimSpace(subject), Message: c.Message, Author: c.Author}
+}
+
+// CommitChanges returns one line per file changed by the given commit,
+// formatted like StagedDiff.Summary, and its unified diff
+func CommitChanges(hash plumbing.Hash) (summary string, patch string, err error) {
+	repo, err := git.PlainOpen(".")
+	if err != nil {
+		return "", "", err
+	}
+	c, err := repo.CommitObject(hash)
+	if err != nil {
+		return "", "", err
+	}
+	tree, err := c.Tree()
+	if err != nil {
+		return "", "", err
+	}
+
+	// a root commit is compared to an empty tree
+	var parentTree *object.Tree
+	if c.NumParents() > 0 {
+		parent, err := c.Parent(0)
+		if err != nil {
+			return "", "", err
+		}
+		if parentTree, err = parent.Tree(); err != nil {
+			return "", "", err
+		}
+	}
+
+	changes, err := object.DiffTree(parentTree, tree)
+	if err != nil {
+		return "", "", err
+	}
+	p, err := changes.Patch()
+	if err != nil {
+		return "", "", err
+	}
+
+	lines := make([]string, 0, len(p.Stats()))
+	for _, stat := range p.Stats() {
+		lines = append(lines, FileStat{Path: stat.Name, Added: stat.Addition, Removed: stat.Deletion}.String())
+	}
+	return strings.Join(lines, "\n"), p.String(), nil
+}
+
+// LogFilter narrows down the commits LogRange returns, like the
+// --author option and path arguments of `git log`
+type LogFilter struct {
+	Authors []string // regular expressions matched against "Name <email>"; any can match
+	Paths   []string // files or directories, relative to the repository root; any can match
+}
+
+// authorMatcher compiles the filter's author patterns
+func (f LogFilter) authorMatcher() (func(object.Signature) bool, error) {
+	if len(f.Authors) == 0 {
+		return func(object.Signature) bool { return true }, nil
+	}
+	patterns := make([]*regexp.Regexp, len(f.Authors))
+	for i, author := range f.Authors {
+		re, err := regexp.Compile(author)
+		if err != nil {
+			return nil, fmt.Errorf("invalid author pattern %q: %w", author, err)
+		}
+		patterns[i] = re
+	}
+	return func(sig object.Signature) bool {
+		identity := sig.Name + " <" + sig.Email + ">"
+		for _, re := range patterns {
+			if re.MatchString(identity) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+// pathFilter matches a changed file against the filter's paths.
+// A path matches itself, anything under it, and glob patterns.
+func (f LogFilter) pathFilter() func(string) bool {
+	if len(f.Paths) == 0 {
+		return nil
+	}
+	paths := make([]string, len(f.Paths))
+	for i, path := range f.Paths {
+		paths[i] = strings.TrimSuffix(filepath.ToSlash(filepath.Clean(path)), "/")
+	}
+	return func(changed string) bool {
+		for _, path := range paths {
+			if path == "." || changed == path || strings.HasPrefix(changed, path+"/") {
+				return true
+			}
+			if matched, _ := filepath.Match(path, changed); matched {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// LogRange returns the commits in rng, newest first, leaving out merges.
+// rng is read like `git log` reads it: A..B is the commits reachable from
+// B but not A, B defaults to HEAD, and a single revision (or "") is all
+// of its history.
+func LogRange(rng string, filter LogFilter) ([]Summary, error) {
+	matchesAuthor, err := filter.authorMatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	repo, err := git.PlainOpen(".")
+	if err != nil {
+		return nil, err
+	}
+
+	from, to, found := strings.Cut(rng, "..")
+	if !found {
+		from, to = "", rng
+	}
+	if to == "" {
+		to = "HEAD"
+	}
+
+	end, err := repo.ResolveRevision(plumbing.Revision(to))
+	if err != nil {
+		return nil, errors.New("unknown revision " + to)
+	}
+
+	excluded := map[plumbing.Hash]bool{}
+	if from != "" {
+		start, err := repo.ResolveRevision(plumbing.Revision(from))
+		if err != nil {
+			return nil, errors.New("unknown revision " + from)
+		}
+		iter, err := repo.Log(&git.LogOptions{From: *start})
+		if err != nil {
+			return nil, err
+		}
+		err = iter.ForEach(func(c *object.Commit) error {
+			excluded[c.Hash] = true
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	iter, err := repo.Log(&git.LogOptions{From: *end, PathFilter: filter.pathFilter()})
+	if err != nil {
+		return nil, err
+	}
+	var commits []Summary
+	err = iter.ForEach(func(c *object.Commit) error {
+		if !excluded[c.Hash] && c.NumParents() < 2 && matchesAuthor(c.Author) {
+			commits = append(commits, summarize(c))
+		}
+		return nil
+	})
+	return commits, err
+}
+
+// ReachableTags returns the names of the tags that point at HEAD or one
+// of its ancestors
+func ReachableTags() ([]string, error) {
+	repo, err := git.PlainOpen(".")
+	if err != nil {
+		return nil, err
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return nil, err
+	}
+
+	ancestors := map[plumbing.Hash]bool{}
+	iter, err := repo.Log(&git.LogOptions{From: head.Hash()})
+	if err != nil {
+		return nil, err
+	}
+	err = iter.ForEach(func(c *object.Commit) error {
+		ancestors[c.Hash] = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := repo.Tags()
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	err = tags.ForEach(func(ref *plumbing.Reference) error {
+		hash := ref.Hash()
+		// annotated tags point at a tag object rather than the commit
+		if tag, err := repo.TagObject(hash); err == nil {
+			hash = tag.Target
+		}
+		if ancestors[hash] {
+			names = append(names, ref.Name().Short())
+		}
+		return nil
+	})
+	return names, err
+}
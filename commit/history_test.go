@@ -0,0 +1,116 @@
+package commit
+
+import (
+	"os"
+	"os/exec"
+	"slices"
+	"testing"
+)
+
+func TestRecentCommits(t *testing.T) {
+	initGitRepo(t)
+	commitFile(t, "a.txt", "first")
+	commitFile(t, "b.txt", "second")
+
+	commits, err := RecentCommits(10)
+	if err != nil {
+		t.Fatalf("RecentCommits() error = %v", err)
+	}
+	if len(commits) != 2 || commits[0].Subject != "second" || commits[1].Subject != "first" {
+		t.Errorf("RecentCommits() = %v", commits)
+	}
+}
+
+func TestLogRange(t *testing.T) {
+	initGitRepo(t)
+	commitFile(t, "a.txt", "first")
+	if err := exec.Command("git", "tag", "v1").Run(); err != nil {
+		t.Fatal(err)
+	}
+	commitFile(t, "b.txt", "second")
+	commitFile(t, "c.txt", "third")
+
+	commits, err := LogRange("v1..", LogFilter{})
+	if err != nil {
+		t.Fatalf("LogRange() error = %v", err)
+	}
+	if len(commits) != 2 || commits[0].Subject != "third" || commits[1].Subject != "second" {
+		t.Errorf("LogRange(v1..) = %v", commits)
+	}
+
+	commits, err = LogRange("", LogFilter{})
+	if err != nil {
+		t.Fatalf("LogRange() error = %v", err)
+	}
+	if len(commits) != 3 {
+		t.Errorf("LogRange(\"\") = %v", commits)
+	}
+
+	if _, err := LogRange("nope..HEAD", LogFilter{}); err == nil {
+		t.Error("expected an error for an unknown revision")
+	}
+}
+
+func TestLogRange_Filter(t *testing.T) {
+	initGitRepo(t)
+	commitFile(t, "a.txt", "first")
+	if err := os.Mkdir("docs", 0755); err != nil {
+		t.Fatal(err)
+	}
+	commitFile(t, "docs/guide.md", "write a guide")
+	cmd := exec.Command("git", "commit", "-q", "--allow-empty", "-m", "by ada")
+	cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=Ada", "GIT_AUTHOR_EMAIL=ada@example.com")
+	if err := cmd.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	commits, err := LogRange("", LogFilter{Paths: []string{"docs"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commits) != 1 || commits[0].Subject != "write a guide" {
+		t.Errorf("path filter = %v", commits)
+	}
+
+	commits, err = LogRange("", LogFilter{Authors: []string{"nobody", "ada@"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commits) != 1 || commits[0].Author.Name != "Ada" {
+		t.Errorf("author filter = %v", commits)
+	}
+
+	if _, err := LogRange("", LogFilter{Authors: []string{"("}}); err == nil {
+		t.Error("expected an error for an invalid author pattern")
+	}
+}
+
+func TestReachableTags(t *testing.T) {
+	initGitRepo(t)
+	commitFile(t, "a.txt", "first")
+	for _, args := range [][]string{
+		{"tag", "v1.0.0"},
+		{"tag", "-a", "v1.1.0", "-m", "annotated"},
+		{"checkout", "-qb", "side"},
+	} {
+		if err := exec.Command("git", args...).Run(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	commitFile(t, "b.txt", "on a branch")
+	if err := exec.Command("git", "tag", "v2.0.0").Run(); err != nil {
+		t.Fatal(err)
+	}
+	if err := exec.Command("git", "checkout", "-q", "-").Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	tags, err := ReachableTags()
+	if err != nil {
+		t.Fatalf("ReachableTags() error = %v", err)
+	}
+	slices.Sort(tags)
+	if !slices.Equal(tags, []string{"v1.0.0", "v1.1.0"}) {
+		t.Errorf("ReachableTags() = %v", tags)
+	}
+}
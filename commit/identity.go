@@ -0,0 +1,217 @@
+package commit
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v6/plumbing/object"
+)
+
+// Options changes how a commit is made and who it is attributed to
+type Options struct {
+	Author      string // "Name <email>", or a pattern matched against existing authors
+	Date        string // the author date, in one of the formats git accepts
+	ResetAuthor bool   // when amending, use the current identity and time instead of the original
+	AllowEmpty  bool   // allow a new commit that doesn't change any files
+}
+
+// identityPattern matches "Name <email>"
+var identityPattern = regexp.MustCompile(`^\s*([^<]*?)\s*<([^>]*)>\s*$`)
+
+// Validate checks that Author and Date can be understood
+func (o Options) Validate() error {
+	if o.Author != "" {
+		if _, _, err := resolveAuthor(o.Author); err != nil {
+			return err
+		}
+	}
+	if o.Date != "" {
+		if _, err := parseDate(o.Date); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// authorSignature works out the author of a new commit the way git does:
+// --author and --date win, then GIT_AUTHOR_NAME, GIT_AUTHOR_EMAIL, and
+// GIT_AUTHOR_DATE, then author.* and user.* from git config.
+func (o Options) authorSignature() (*object.Signature, error) {
+	name := firstNonEmpty(os.Getenv("GIT_AUTHOR_NAME"), gitConfigValue("author.name"), gitConfigValue("user.name"))
+	if name == "" {
+		return nil, errors.New("author field is required: could not get user.name from git config")
+	}
+	email := firstNonEmpty(os.Getenv("GIT_AUTHOR_EMAIL"), gitConfigValue("author.email"), gitConfigValue("user.email"), os.Getenv("EMAIL"))
+	if email == "" {
+		return nil, errors.New("author field is required: could not get user.email from git config")
+	}
+	when, err := dateFromEnv("GIT_AUTHOR_DATE")
+	if err != nil {
+		return nil, err
+	}
+
+	author := &object.Signature{Name: name, Email: email, When: when}
+	return author, o.applyOverrides(author)
+}
+
+// amendAuthorSignature keeps the author of original unless ResetAuthor is
+// set, like `git commit --amend`. --author and --date still apply.
+func (o Options) amendAuthorSignature(original *object.Commit) (*object.Signature, error) {
+	if o.ResetAuthor {
+		return o.authorSignature()
+	}
+	author := original.Author
+	return &author, o.applyOverrides(&author)
+}
+
+// applyOverrides applies --author and --date to author
+func (o Options) applyOverrides(author *object.Signature) error {
+	if o.Author != "" {
+		name, email, err := resolveAuthor(o.Author)
+		if err != nil {
+			return err
+		}
+		author.Name, author.Email = name, email
+	}
+	if o.Date != "" {
+		when, err := parseDate(o.Date)
+		if err != nil {
+			return err
+		}
+		author.When = when
+	}
+	return nil
+}
+
+// committerSignature works out the committer the way git does:
+// GIT_COMMITTER_NAME, GIT_COMMITTER_EMAIL, and GIT_COMMITTER_DATE,
+// then committer.* and user.* from git config
+func committerSignature() (*object.Signature, error) {
+	name := firstNonEmpty(os.Getenv("GIT_COMMITTER_NAME"), gitConfigValue("committer.name"), gitConfigValue("user.name"))
+	if name == "" {
+		return nil, errors.New("committer is required: could not get user.name from git config")
+	}
+	email := firstNonEmpty(os.Getenv("GIT_COMMITTER_EMAIL"), gitConfigValue("committer.email"), gitConfigValue("user.email"), os.Getenv("EMAIL"))
+	if email == "" {
+		return nil, errors.New("committer is required: could not get user.email from git config")
+	}
+	when, err := dateFromEnv("GIT_COMMITTER_DATE")
+	if err != nil {
+		return nil, err
+	}
+	return &object.Signature{Name: name, Email: email, When: when}, nil
+}
+
+// resolveAuthor parses "Name <email>". Anything else is treated the way
+// `git commit --author` does: as a pattern to find an existing author by.
+func resolveAuthor(author string) (string, string, error) {
+	if match := identityPattern.FindStringSubmatch(author); match != nil {
+		return match[1], match[2], nil
+	}
+
+	output, err := exec.Command("git", "log", "--all", "-i", "-1", "--format=%an <%ae>", "--author="+author).Output()
+	if err == nil {
+		if match := identityPattern.FindStringSubmatch(strings.TrimSpace(string(output))); match != nil {
+			return match[1], match[2], nil
+		}
+	}
+	return "", "", fmt.Errorf("--author '%s' is not 'Name <email>' and matches no existing author", author)
+}
+
+// dateFromEnv parses the date in the named environment variable,
+// defaulting to now
+func dateFromEnv(name string) (time.Time, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return time.Now(), nil
+	}
+	when, err := parseDate(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s: %w", name, err)
+	}
+	return when, nil
+}
+
+// rawDatePattern matches git's internal format: "<unix timestamp> <zone>",
+// optionally with an @ before the timestamp
+var rawDatePattern = regexp.MustCompile(`^@?(\d+)(?:\s+([+-])(\d{2})(\d{2}))?$`)
+
+// dateLayouts are the other formats git documents for --date and
+// GIT_AUTHOR_DATE: RFC 2822 and ISO 8601
+var dateLayouts = []string{
+	time.RFC1123Z,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"2 Jan 2006 15:04:05 -0700",
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999-0700",
+	"2006-01-02 15:04:05.999999999 -0700",
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999Z07:00",
+}
+
+// localDateLayouts have no time zone, so they're in local time
+var localDateLayouts = []string{
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+	"2006.01.02 15:04:05",
+	"2006-01-02",
+	"2006.01.02",
+}
+
+// parseDate parses a date in one of the formats git accepts
+func parseDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+
+	if match := rawDatePattern.FindStringSubmatch(value); match != nil {
+		seconds, err := strconv.ParseInt(match[1], 10, 64)
+		if err == nil {
+			when := time.Unix(seconds, 0)
+			if match[2] != "" {
+				hours, _ := strconv.Atoi(match[3])
+				minutes, _ := strconv.Atoi(match[4])
+				offset := hours*3600 + minutes*60
+				if match[2] == "-" {
+					offset = -offset
+				}
+				when = when.In(time.FixedZone("", offset))
+			}
+			return when, nil
+		}
+	}
+
+	for _, layout := range dateLayouts {
+		if when, err := time.Parse(layout, value); err == nil {
+			return when, nil
+		}
+	}
+	for _, layout := range localDateLayouts {
+		if when, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return when, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date format: %s", value)
+}
+
+// gitConfigValue returns a value from git config, or "" if it isn't set
+func gitConfigValue(key string) string {
+	value, err := runGitConfig(key)
+	if err != nil {
+		return ""
+	}
+	return value
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,121 @@
+package commit
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+// clearIdentityEnv stops the environment running the tests from
+// changing who commits are attributed to
+func clearIdentityEnv(t *testing.T) {
+	t.Helper()
+	for _, name := range []string{
+		"GIT_AUTHOR_NAME", "GIT_AUTHOR_EMAIL", "GIT_AUTHOR_DATE",
+		"GIT_COMMITTER_NAME", "GIT_COMMITTER_EMAIL", "GIT_COMMITTER_DATE",
+	} {
+		t.Setenv(name, "")
+		os.Unsetenv(name)
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	want := time.Date(2005, 4, 7, 22, 13, 13, 0, time.FixedZone("", 2*3600))
+	for _, value := range []string{
+		"1112904793 +0200",
+		"@1112904793 +0200",
+		"Thu, 07 Apr 2005 22:13:13 +0200",
+		"2005-04-07T22:13:13+02:00",
+		"2005-04-07 22:13:13 +0200",
+	} {
+		got, err := parseDate(value)
+		if err != nil {
+			t.Errorf("parseDate(%q): %v", value, err)
+			continue
+		}
+		if !got.Equal(want) {
+			t.Errorf("parseDate(%q) = %v, want %v", value, got, want)
+		}
+		if _, offset := got.Zone(); offset != 2*3600 {
+			t.Errorf("parseDate(%q) has offset %d, want %d", value, offset, 2*3600)
+		}
+	}
+
+	if _, err := parseDate("next tuesday"); err == nil {
+		t.Error("expected an error for an unsupported date")
+	}
+}
+
+func TestCreateCommit_AuthorAndCommitter(t *testing.T) {
+	initGitRepo(t)
+	clearIdentityEnv(t)
+	t.Setenv("GIT_COMMITTER_NAME", "Bot")
+	t.Setenv("GIT_COMMITTER_EMAIL", "bot@example.com")
+	writeFile(t, "a.txt", "a")
+	if err := exec.Command("git", "add", "a.txt").Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := Options{Author: "Ada <ada@example.com>", Date: "1112904793 +0200"}
+	if err := CreateCommit("add a", "", opts); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := gitOutput(t, "log", "-1", "--format=%an <%ae> %ad", "--date=raw"); got != "Ada <ada@example.com> 1112904793 +0200" {
+		t.Errorf("author = %q", got)
+	}
+	if got := gitOutput(t, "log", "-1", "--format=%cn <%ce>"); got != "Bot <bot@example.com>" {
+		t.Errorf("committer = %q", got)
+	}
+}
+
+func TestAmendCommit_KeepsAuthor(t *testing.T) {
+	initGitRepo(t)
+	clearIdentityEnv(t)
+	t.Setenv("GIT_AUTHOR_NAME", "Ada")
+	t.Setenv("GIT_AUTHOR_EMAIL", "ada@example.com")
+	t.Setenv("GIT_AUTHOR_DATE", "1112904793 +0200")
+	commitFile(t, "a.txt", "add a")
+	clearIdentityEnv(t)
+
+	if err := AmendCommit("add a file", "", Options{}); err != nil {
+		t.Fatal(err)
+	}
+	if got := gitOutput(t, "log", "-1", "--format=%an %ad %s", "--date=raw"); got != "Ada 1112904793 +0200 add a file" {
+		t.Errorf("after amend = %q", got)
+	}
+	if got := gitOutput(t, "rev-list", "--count", "HEAD"); got != "1" {
+		t.Errorf("commit count = %s, want 1", got)
+	}
+
+	if err := AmendCommit("add a file", "", Options{ResetAuthor: true}); err != nil {
+		t.Fatal(err)
+	}
+	if got := gitOutput(t, "log", "-1", "--format=%an"); got != "Test" {
+		t.Errorf("after --reset-author author = %q, want Test", got)
+	}
+	if got := gitOutput(t, "log", "-1", "--format=%ad", "--date=raw"); got == "1112904793 +0200" {
+		t.Error("--reset-author kept the original author date")
+	}
+}
+
+func TestResolveAuthor_Pattern(t *testing.T) {
+	initGitRepo(t)
+	clearIdentityEnv(t)
+	t.Setenv("GIT_AUTHOR_NAME", "Grace Hopper")
+	t.Setenv("GIT_AUTHOR_EMAIL", "grace@example.com")
+	commitFile(t, "a.txt", "add a")
+
+	name, email, err := resolveAuthor("hopper")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "Grace Hopper" || email != "grace@example.com" {
+		t.Errorf("resolveAuthor = %q, %q", name, email)
+	}
+
+	if _, _, err := resolveAuthor("nobody"); err == nil {
+		t.Error("expected an error for a pattern matching no author")
+	}
+}
@@ -0,0 +1,262 @@
+package commit
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	git "github.com/go-git/go-git/v6"
+	"github.com/go-git/go-git/v6/plumbing"
+	"github.com/go-git/go-git/v6/plumbing/object"
+)
+
+// ErrMergeInHistory is returned when a commit that would need to be
+// rewritten has more than one parent
+var ErrMergeInHistory = errors.New("can't rewrite history containing merge commits")
+
+// ErrSignedInHistory is returned when a commit that would need to be
+// rewritten is signed, since the signature can't be carried over
+var ErrSignedInHistory = errors.New("can't rewrite signed commits without losing their signatures")
+
+// Reword changes the message of target, which must be HEAD or one of
+// its first-parent ancestors, and replays the commits that follow it.
+// Trees are untouched, so the worktree and index are unaffected.
+func Reword(target plumbing.Hash, title, body string) error {
+	return RewriteMessages(map[plumbing.Hash]string{
+		target: BuildCommitMessage(title, body) + "\n",
+	})
+}
+
+// RewriteMessages replaces the messages of the given commits, which must
+// all be first-parent ancestors of HEAD (or HEAD itself), and replays
+// everything after the oldest of them.
+// Trees are untouched, so the worktree and index are unaffected.
+func RewriteMessages(messages map[plumbing.Hash]string) error {
+	repo, err := git.PlainOpen(".")
+	if err != nil {
+		return err
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return err
+	}
+
+	// collect HEAD back to the oldest commit being changed, newest first
+	var chain []*object.Commit
+	remaining := len(messages)
+	for hash := head.Hash(); remaining > 0; {
+		c, err := repo.CommitObject(hash)
+		if err != nil {
+			return err
+		}
+		if err := checkRewritable(c); err != nil {
+			return err
+		}
+		chain = append(chain, c)
+		if _, ok := messages[hash]; ok {
+			remaining--
+		}
+		if remaining == 0 {
+			break
+		}
+		if c.NumParents() == 0 {
+			return errors.New("can only reword commits that are ancestors of HEAD")
+		}
+		hash = c.ParentHashes[0]
+	}
+	if len(chain) == 0 {
+		return nil
+	}
+
+	committer, err := committerSignature()
+	if err != nil {
+		return err
+	}
+
+	// rewrite the oldest commit, then each descendant onto it
+	var newParent plumbing.Hash
+	for i := len(chain) - 1; i >= 0; i-- {
+		c := chain[i]
+		rewritten := &object.Commit{
+			Author:       c.Author,
+			Committer:    *committer,
+			Message:      c.Message,
+			TreeHash:     c.TreeHash,
+			ParentHashes: c.ParentHashes,
+			Encoding:     c.Encoding,
+			ExtraHeaders: c.ExtraHeaders,
+		}
+		if message, ok := messages[c.Hash]; ok {
+			rewritten.Message = message
+		}
+		if i < len(chain)-1 {
+			rewritten.ParentHashes = []plumbing.Hash{newParent}
+		}
+
+		newParent, err = storeCommit(repo, rewritten)
+		if err != nil {
+			return err
+		}
+	}
+
+	return moveHead(repo, head, newParent)
+}
+
+// CommitRange returns the commits in rng, oldest first. rng is either a
+// number of commits back from HEAD, or A..B where B is HEAD (or omitted).
+// Like git, A..B starts from where A and B diverged, so main..HEAD is
+// the commits of a branch even if main has moved on.
+// Only first-parent history can be rewritten, so merges are an error,
+// and so are signed commits.
+func CommitRange(rng string) ([]Summary, error) {
+	repo, err := git.PlainOpen(".")
+	if err != nil {
+		return nil, err
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return nil, err
+	}
+
+	count := -1
+	var stop plumbing.Hash
+	if n, err := strconv.Atoi(rng); err == nil {
+		if n < 1 {
+			return nil, errors.New("the number of commits must be at least 1")
+		}
+		count = n
+	} else {
+		from, to, found := strings.Cut(rng, "..")
+		if !found || from == "" {
+			return nil, errors.New("expected a range like HEAD~5..HEAD, or a number of commits")
+		}
+		if to != "" {
+			end, err := repo.ResolveRevision(plumbing.Revision(to))
+			if err != nil {
+				return nil, errors.New("unknown revision " + to)
+			}
+			if *end != head.Hash() {
+				return nil, errors.New("the range must end at HEAD")
+			}
+		}
+		start, err := repo.ResolveRevision(plumbing.Revision(from))
+		if err != nil {
+			return nil, errors.New("unknown revision " + from)
+		}
+		stop, err = mergeBase(repo, *start, head.Hash())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var commits []Summary
+	for hash := head.Hash(); hash != stop && count != 0; count-- {
+		c, err := repo.CommitObject(hash)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkRewritable(c); err != nil {
+			return nil, err
+		}
+		commits = append(commits, summarize(c))
+		if c.NumParents() == 0 {
+			if !stop.IsZero() {
+				return nil, errors.New(rng + ": the start of the range is not an ancestor of HEAD")
+			}
+			break
+		}
+		hash = c.ParentHashes[0]
+	}
+
+	slices.Reverse(commits)
+	return commits, nil
+}
+
+// SaveBackup points refs/git-com/backup/<branch> at HEAD so a rewrite
+// can be undone. Returns the name of the ref.
+func SaveBackup() (string, error) {
+	repo, err := git.PlainOpen(".")
+	if err != nil {
+		return "", err
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return "", err
+	}
+
+	name := "HEAD"
+	if head.Name().IsBranch() {
+		name = head.Name().Short()
+	}
+	ref := plumbing.ReferenceName("refs/git-com/backup/" + name)
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(ref, head.Hash())); err != nil {
+		return "", err
+	}
+	return ref.String(), nil
+}
+
+// mergeBase returns the commit a and b last had in common
+func mergeBase(repo *git.Repository, a, b plumbing.Hash) (plumbing.Hash, error) {
+	ac, err := repo.CommitObject(a)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	bc, err := repo.CommitObject(b)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	bases, err := ac.MergeBase(bc)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	if len(bases) == 0 {
+		return plumbing.ZeroHash, errors.New("the start of the range has no history in common with HEAD")
+	}
+	return bases[0].Hash, nil
+}
+
+// checkRewritable returns an error explaining why c can't be rewritten,
+// if it can't be
+func checkRewritable(c *object.Commit) error {
+	if c.NumParents() > 1 {
+		return fmt.Errorf("%w (%s)", ErrMergeInHistory, summarize(c))
+	}
+	if isSigned(c) {
+		return fmt.Errorf("%w (%s)", ErrSignedInHistory, summarize(c))
+	}
+	return nil
+}
+
+// isSigned returns true if c has a signature, of any kind
+func isSigned(c *object.Commit) bool {
+	if c.PGPSignature != "" {
+		return true
+	}
+	for _, header := range c.ExtraHeaders {
+		if strings.HasPrefix(header.Key, "gpgsig") {
+			return true
+		}
+	}
+	return false
+}
+
+// storeCommit writes a commit object and returns its hash
+func storeCommit(repo *git.Repository, c *object.Commit) (plumbing.Hash, error) {
+	obj := repo.Storer.NewEncodedObject()
+	if err := c.Encode(obj); err != nil {
+		return plumbing.ZeroHash, err
+	}
+	return repo.Storer.SetEncodedObject(obj)
+}
+
+// moveHead points the current branch (or a detached HEAD) at hash
+func moveHead(repo *git.Repository, head *plumbing.Reference, hash plumbing.Hash) error {
+	name := head.Name()
+	if name == plumbing.HEAD {
+		return repo.Storer.SetReference(plumbing.NewHashReference(plumbing.HEAD, hash))
+	}
+	return repo.Storer.CheckAndSetReference(plumbing.NewHashReference(name, hash), head)
+}
@@ -0,0 +1,200 @@
+package commit
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+
+	git "github.com/go-git/go-git/v6"
+	"github.com/go-git/go-git/v6/plumbing"
+)
+
+// commitFile commits a file with the given message
+func commitFile(t *testing.T, name, message string) {
+	t.Helper()
+	writeFile(t, name, message)
+	for _, args := range [][]string{{"add", name}, {"commit", "-qm", message}} {
+		if err := exec.Command("git", args...).Run(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func gitOutput(t *testing.T, args ...string) string {
+	t.Helper()
+	out, err := exec.Command("git", args...).Output()
+	if err != nil {
+		t.Fatalf("git %v: %v", args, err)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func TestReword(t *testing.T) {
+	initGitRepo(t)
+	commitFile(t, "a.txt", "first")
+	commitFile(t, "b.txt", "second")
+	commitFile(t, "c.txt", "third")
+	treeBefore := gitOutput(t, "rev-parse", "HEAD^{tree}")
+
+	target, err := ResolveCommit("HEAD~1")
+	if err != nil {
+		t.Fatalf("ResolveCommit() error = %v", err)
+	}
+	if target.Subject != "second" {
+		t.Fatalf("ResolveCommit(HEAD~1).Subject = %q, want %q", target.Subject, "second")
+	}
+
+	if short, err := ResolveCommit(target.Hash.String()[:7]); err != nil || short.Hash != target.Hash {
+		t.Errorf("ResolveCommit(short hash) = %v, %v", short, err)
+	}
+
+	if err := Reword(target.Hash, "second, reworded", "with a body"); err != nil {
+		t.Fatalf("Reword() error = %v", err)
+	}
+
+	if got := gitOutput(t, "log", "--format=%s", "-3"); got != "third\nsecond, reworded\nfirst" {
+		t.Errorf("log after Reword() = %q", got)
+	}
+	if got := gitOutput(t, "log", "--format=%b", "-1", "HEAD~1"); got != "with a body" {
+		t.Errorf("reworded body = %q, want %q", got, "with a body")
+	}
+	if got := gitOutput(t, "rev-parse", "HEAD^{tree}"); got != treeBefore {
+		t.Errorf("HEAD tree changed from %s to %s", treeBefore, got)
+	}
+	if got := gitOutput(t, "status", "--porcelain"); got != "" {
+		t.Errorf("worktree isn't clean after Reword(): %q", got)
+	}
+}
+
+func TestRewriteMessages_Range(t *testing.T) {
+	initGitRepo(t)
+	commitFile(t, "a.txt", "first")
+	commitFile(t, "b.txt", "second")
+	commitFile(t, "c.txt", "third")
+	commitFile(t, "d.txt", "fourth")
+	oldHead := gitOutput(t, "rev-parse", "HEAD")
+
+	commits, err := CommitRange("HEAD~3..HEAD")
+	if err != nil {
+		t.Fatalf("CommitRange() error = %v", err)
+	}
+	var subjects []string
+	for _, c := range commits {
+		subjects = append(subjects, c.Subject)
+	}
+	if strings.Join(subjects, ",") != "second,third,fourth" {
+		t.Fatalf("CommitRange() = %v, want second, third, fourth", subjects)
+	}
+	if byCount, _ := CommitRange("3"); len(byCount) != 3 || byCount[0].Hash != commits[0].Hash {
+		t.Errorf("CommitRange(\"3\") = %v, want the same commits", byCount)
+	}
+
+	backup, err := SaveBackup()
+	if err != nil {
+		t.Fatalf("SaveBackup() error = %v", err)
+	}
+	err = RewriteMessages(map[plumbing.Hash]string{
+		commits[0].Hash: "2nd\n",
+		commits[2].Hash: "4th\n",
+	})
+	if err != nil {
+		t.Fatalf("RewriteMessages() error = %v", err)
+	}
+
+	if got := gitOutput(t, "log", "--format=%s"); got != "4th\nthird\n2nd\nfirst" {
+		t.Errorf("log after RewriteMessages() = %q", got)
+	}
+	if got := gitOutput(t, "rev-parse", backup); got != oldHead {
+		t.Errorf("%s = %s, want the old HEAD %s", backup, got, oldHead)
+	}
+}
+
+func TestCommitRange_Merge(t *testing.T) {
+	initGitRepo(t)
+	commitFile(t, "a.txt", "first")
+	for _, args := range [][]string{
+		{"checkout", "-qb", "side"},
+		{"commit", "-q", "--allow-empty", "-m", "side"},
+		{"checkout", "-q", "-"},
+		{"commit", "-q", "--allow-empty", "-m", "main"},
+		{"merge", "-q", "--no-edit", "side"},
+	} {
+		if err := exec.Command("git", args...).Run(); err != nil {
+			t.Fatalf("git %v: %v", args, err)
+		}
+	}
+
+	if _, err := CommitRange("2"); !errors.Is(err, ErrMergeInHistory) {
+		t.Errorf("CommitRange() error = %v, want ErrMergeInHistory", err)
+	}
+}
+
+func TestCommitRange_Diverged(t *testing.T) {
+	initGitRepo(t)
+	commitFile(t, "a.txt", "first")
+	if err := exec.Command("git", "branch", "base").Run(); err != nil {
+		t.Fatal(err)
+	}
+	commitFile(t, "b.txt", "mine")
+	commitFile(t, "c.txt", "also mine")
+	for _, args := range [][]string{{"checkout", "-q", "base"}, {"commit", "-q", "--allow-empty", "-m", "theirs"}, {"checkout", "-q", "-"}} {
+		if err := exec.Command("git", args...).Run(); err != nil {
+			t.Fatalf("git %v: %v", args, err)
+		}
+	}
+
+	commits, err := CommitRange("base..HEAD")
+	if err != nil {
+		t.Fatalf("CommitRange() error = %v", err)
+	}
+	var subjects []string
+	for _, c := range commits {
+		subjects = append(subjects, c.Subject)
+	}
+	if strings.Join(subjects, ",") != "mine,also mine" {
+		t.Errorf("CommitRange() = %v, want mine, also mine", subjects)
+	}
+}
+
+func TestRewriteMessages_Signed(t *testing.T) {
+	initGitRepo(t)
+	commitFile(t, "a.txt", "first")
+	commitFile(t, "b.txt", "second")
+
+	// sign HEAD, as far as anyone reading it can tell
+	repo, err := git.PlainOpen(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.PGPSignature = "-----BEGIN PGP SIGNATURE-----\n\nnot really\n-----END PGP SIGNATURE-----\n"
+	signed, err := storeCommit(repo, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := exec.Command("git", "reset", "-q", "--soft", signed.String()).Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := CommitRange("2"); !errors.Is(err, ErrSignedInHistory) {
+		t.Errorf("CommitRange() error = %v, want ErrSignedInHistory", err)
+	}
+	target, err := ResolveCommit("HEAD~1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Reword(target.Hash, "1st", ""); !errors.Is(err, ErrSignedInHistory) {
+		t.Errorf("Reword() error = %v, want ErrSignedInHistory", err)
+	}
+	if got := gitOutput(t, "rev-parse", "HEAD"); got != signed.String() {
+		t.Errorf("HEAD moved to %s", got)
+	}
+}
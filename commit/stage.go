@@ -0,0 +1,92 @@
+package commit
+
+import (
+	"errors"
+	"os/exec"
+	"sort"
+	"strings"
+
+	git "github.com/go-git/go-git/v6"
+)
+
+// Change is a path with changes that haven't been staged
+type Change struct {
+	Path   string
+	Status string // a `git status --short` style code, e.g. "M", "D", "??"
+}
+
+// String formats the change like `git status --short`
+func (c Change) String() string {
+	return c.Status + " " + c.Path
+}
+
+// UnstagedChanges returns the modified, deleted, and untracked paths
+// in the worktree, sorted by path
+func UnstagedChanges() ([]Change, error) {
+	repo, err := git.PlainOpen(".")
+	if err != nil {
+		return nil, err
+	}
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := wt.Status()
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []Change
+	for path, s := range status {
+		switch s.Worktree {
+		case git.Unmodified:
+			continue
+		case git.Untracked:
+			changes = append(changes, Change{Path: path, Status: "??"})
+		default:
+			changes = append(changes, Change{Path: path, Status: string(s.Worktree)})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes, nil
+}
+
+// StageFiles adds paths to the index.
+// Deleted paths are staged as deletions.
+func StageFiles(paths []string) error {
+	return runGitAdd(append([]string{"--all", "--"}, paths...))
+}
+
+// StageTrackedChanges stages every change to tracked files,
+// like `git commit -a`
+func StageTrackedChanges() error {
+	return runGitAdd([]string{"--update"})
+}
+
+// runGitAdd runs `git add` from the repository root so that
+// paths from Worktree.Status resolve correctly
+func runGitAdd(args []string) error {
+	root, err := exec.Command("git", "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command("git", append([]string{"add"}, args...)...)
+	cmd.Dir = strings.TrimSpace(string(root))
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return gitError(output, err)
+	}
+	return nil
+}
+
+// gitError prefers git's own message over "exit status 1"
+func gitError(output []byte, err error) error {
+	if msg := strings.TrimSpace(string(output)); msg != "" {
+		return errors.New(msg)
+	}
+	return err
+}
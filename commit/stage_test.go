@@ -0,0 +1,78 @@
+package commit
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+)
+
+// initGitRepo creates an empty repository in a temp dir and changes into it
+func initGitRepo(t *testing.T) {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"config", "user.email", "test@example.com"},
+		{"config", "user.name", "Test"},
+	} {
+		if err := exec.Command("git", args...).Run(); err != nil {
+			t.Skip("git not available")
+		}
+	}
+}
+
+func writeFile(t *testing.T, path, contents string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUnstagedChanges(t *testing.T) {
+	initGitRepo(t)
+	writeFile(t, "modified.txt", "a")
+	writeFile(t, "deleted.txt", "a")
+	if err := exec.Command("git", "add", ".").Run(); err != nil {
+		t.Fatal(err)
+	}
+	if err := exec.Command("git", "commit", "-qm", "initial").Run(); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, "modified.txt", "b")
+	os.Remove("deleted.txt")
+	writeFile(t, "new.txt", "a")
+
+	changes, err := UnstagedChanges()
+	if err != nil {
+		t.Fatalf("UnstagedChanges() error = %v", err)
+	}
+	want := []string{"D deleted.txt", "M modified.txt", "?? new.txt"}
+	if len(changes) != len(want) {
+		t.Fatalf("UnstagedChanges() = %v, want %v", changes, want)
+	}
+	for i, change := range changes {
+		if change.String() != want[i] {
+			t.Errorf("changes[%d] = %q, want %q", i, change.String(), want[i])
+		}
+	}
+
+	if err := StageFiles([]string{"deleted.txt", "new.txt"}); err != nil {
+		t.Fatalf("StageFiles() error = %v", err)
+	}
+	hasStaged, err := HasStagedFiles()
+	if err != nil || !hasStaged {
+		t.Errorf("HasStagedFiles() = %v, %v after StageFiles", hasStaged, err)
+	}
+	changes, _ = UnstagedChanges()
+	if len(changes) != 1 || changes[0].Path != "modified.txt" {
+		t.Errorf("UnstagedChanges() after staging = %v, want only modified.txt", changes)
+	}
+
+	if err := StageTrackedChanges(); err != nil {
+		t.Fatalf("StageTrackedChanges() error = %v", err)
+	}
+	changes, _ = UnstagedChanges()
+	if len(changes) != 0 {
+		t.Errorf("UnstagedChanges() after StageTrackedChanges = %v, want none", changes)
+	}
+}
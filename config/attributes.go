@@ -0,0 +1,52 @@
+package config
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// knownAttributes returns the set of attribute names an element may use,
+// taken from the yaml tags on Element
+func knownAttributes() map[string]bool {
+	known := make(map[string]bool)
+	for _, name := range attributeNames() {
+		known[name] = true
+	}
+	return known
+}
+
+// attributeNames returns every attribute name from Element's yaml tags, sorted
+func attributeNames() []string {
+	var names []string
+	t := reflect.TypeOf(Element{})
+	for i := 0; i < t.NumField(); i++ {
+		if name := attributeName(t.Field(i)); name != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// attributeValue returns the Element field for the attribute attr
+func attributeValue(elem Element, attr string) (reflect.Value, bool) {
+	v := reflect.ValueOf(elem)
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if attributeName(t.Field(i)) == attr {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// attributeName returns the yaml name of an Element field,
+// or an empty string if it isn't an attribute
+func attributeName(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+	if name == "-" {
+		return ""
+	}
+	return name
+}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"strings"
+	"text/template"
+)
+
+// computedFuncs are available in a computed element's template, on top
+// of TemplateFuncs. They look at the repository, so they're only run
+// when the element is, never while validating.
+var computedFuncs = template.FuncMap{
+	"branch": templateBranch,
+	"git":    templateGit,
+	"env":    os.Getenv,
+}
+
+// templateBranch returns the current branch, or "" on a detached HEAD:
+// {{branch}}
+func templateBranch() (string, error) {
+	return templateGit("branch", "--show-current")
+}
+
+// templateGit runs git with args and returns its trimmed output:
+// {{git "config" "user.name"}}
+func templateGit(args ...string) (string, error) {
+	output, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w", strings.Join(args, " "), err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// parseComputedTemplate parses a computed element's template
+func parseComputedTemplate(elem Element) (*template.Template, error) {
+	funcs := maps.Clone(TemplateFuncs)
+	maps.Copy(funcs, computedFuncs)
+	return template.New(elem.Name).Funcs(funcs).Option("missingkey=error").Parse(elem.Template)
+}
+
+// ComputeValue works out a computed element's value from the values of
+// the elements before it, keyed by element name. A template is given
+// the same data as title-template; a command is run with sh and gets
+// each value in GIT_COM_<NAME>. Surrounding whitespace is trimmed.
+func (c *Config) ComputeValue(elem Element, values map[string]string) (string, error) {
+	if elem.Command != "" {
+		return runComputedCommand(elem, values)
+	}
+
+	tmpl, err := parseComputedTemplate(elem)
+	if err != nil {
+		return "", err
+	}
+	var s strings.Builder
+	if err := tmpl.Execute(&s, c.TemplateData(values)); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(s.String()), nil
+}
+
+// runComputedCommand runs a computed element's command
+func runComputedCommand(elem Element, values map[string]string) (string, error) {
+	cmd := exec.Command("sh", "-c", elem.Command)
+	cmd.Env = os.Environ()
+	for name, value := range values {
+		cmd.Env = append(cmd.Env, ComputedEnvName(name)+"="+value)
+	}
+	cmd.Stderr = os.Stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("%s: command failed: %w", elem.Name, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// ComputedEnvName returns the environment variable an element's value
+// is passed to commands in: change-type becomes GIT_COM_CHANGE_TYPE
+func ComputedEnvName(name string) string {
+	name = strings.ToUpper(name)
+	name = strings.Map(func(r rune) rune {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, name)
+	return "GIT_COM_" + name
+}
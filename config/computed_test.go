@@ -0,0 +1,76 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+const computedConfig = `title-template: '{{.change_type}}: {{.subject}}'
+change-type:
+  destination: title
+  type: select
+  options: [fix, feat]
+subject:
+  destination: title
+  type: text
+ticket:
+  destination: body
+  type: computed
+  template: '{{upper .change_type}}-{{env "GIT_COM_TEST_TICKET"}}'
+echoed:
+  destination: body
+  type: computed
+  command: echo "$GIT_COM_CHANGE_TYPE and $GIT_COM_TICKET"
+`
+
+func TestComputeValue(t *testing.T) {
+	t.Setenv("GIT_COM_TEST_TICKET", "42")
+	cfg := loadTemplatedConfig(t, computedConfig)
+	if problems := CheckConfig(cfg); len(problems) != 0 {
+		t.Fatalf("CheckConfig() = %v", problems)
+	}
+
+	values := map[string]string{"change-type": "fix", "subject": "handle empty input"}
+	ticket, err := cfg.ComputeValue(cfg.Elements[2], values)
+	if err != nil {
+		t.Fatalf("ComputeValue(ticket) error = %v", err)
+	}
+	if ticket != "FIX-42" {
+		t.Errorf("ComputeValue(ticket) = %q, want %q", ticket, "FIX-42")
+	}
+
+	// later elements see the computed value like any other
+	values["ticket"] = ticket
+	echoed, err := cfg.ComputeValue(cfg.Elements[3], values)
+	if err != nil {
+		t.Fatalf("ComputeValue(echoed) error = %v", err)
+	}
+	if echoed != "fix and FIX-42" {
+		t.Errorf("ComputeValue(echoed) = %q, want %q", echoed, "fix and FIX-42")
+	}
+}
+
+func TestComputeValue_Errors(t *testing.T) {
+	cfg := &Config{}
+	_, err := cfg.ComputeValue(Element{Name: "fails", Type: TypeComputed, Command: "exit 3"}, nil)
+	if err == nil || !strings.Contains(err.Error(), "fails: command failed") {
+		t.Errorf("ComputeValue(failing command) error = %v", err)
+	}
+	_, err = cfg.ComputeValue(Element{Name: "missing", Type: TypeComputed, Template: "{{.nope}}"}, nil)
+	if err == nil {
+		t.Error("ComputeValue(unknown element) expected an error")
+	}
+}
+
+func TestComputedEnvName(t *testing.T) {
+	tests := map[string]string{
+		"change-type": "GIT_COM_CHANGE_TYPE",
+		"ticket":      "GIT_COM_TICKET",
+		"a.b c2":      "GIT_COM_A_B_C2",
+	}
+	for name, want := range tests {
+		if got := ComputedEnvName(name); got != want {
+			t.Errorf("ComputedEnvName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
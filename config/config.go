@@ -2,6 +2,7 @@ package config
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -29,7 +30,7 @@ func LoadConfig() (*Config, error) {
 		configPath := filepath.Join(gitRoot, fileName)
 		cfg, err := LoadConfigFromPath(configPath)
 		if err == nil {
-			return cfg, nil
+			return cfg, cfg.mergeLocalOptions()
 		}
 		if err != ErrConfigNotFound {
 			return nil, err
@@ -39,6 +40,16 @@ func LoadConfig() (*Config, error) {
 	return nil, ErrConfigNotFound
 }
 
+// DefaultConfigPath returns the path a new config file should be written to.
+// That's .git-com.yaml at the root of the current git repository.
+func DefaultConfigPath() (string, error) {
+	gitRoot, err := findGitRoot()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(gitRoot, configFileNames[0]), nil
+}
+
 // LoadConfigFromPath loads the configuration from a specific path
 func LoadConfigFromPath(path string) (*Config, error) {
 	data, err := os.ReadFile(path)
@@ -49,33 +60,46 @@ func LoadConfigFromPath(path string) (*Config, error) {
 		return nil, err
 	}
 
-	elements, err := parseOrderedYAML(data)
+	document, elements, err := parseDocument(data)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%s: %w", path, err)
 	}
 
-	return &Config{
+	cfg := &Config{
 		Elements: elements,
 		FilePath: path,
-	}, nil
+		source:   data,
+		document: document,
+	}
+	if err := parseTemplates(document, cfg); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
 }
 
 // parseOrderedYAML parses YAML while preserving the order of elements
 func parseOrderedYAML(data []byte) ([]Element, error) {
+	_, elements, err := parseDocument(data)
+	return elements, err
+}
+
+// parseDocument parses YAML into both the raw node tree and the
+// ordered list of elements it describes
+func parseDocument(data []byte) (*yaml.Node, []Element, error) {
 	var node yaml.Node
 	if err := yaml.Unmarshal(data, &node); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// Handle empty file
 	if len(node.Content) == 0 {
-		return nil, nil
+		return nil, nil, nil
 	}
 
 	// node.Content[0] is the document root (a MappingNode)
 	docNode := node.Content[0]
 	if docNode.Kind != yaml.MappingNode {
-		return nil, errors.New("expected a mapping at the root of the YAML")
+		return nil, nil, errors.New("expected a mapping at the root of the YAML")
 	}
 
 	// Content contains alternating key/value nodes
@@ -84,16 +108,57 @@ func parseOrderedYAML(data []byte) ([]Element, error) {
 	for i := 0; i < len(content); i += 2 {
 		keyNode := content[i]
 		valueNode := content[i+1]
+		if isTemplateKey(keyNode.Value) {
+			continue
+		}
 
 		var elem Element
 		if err := valueNode.Decode(&elem); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		elem.Name = keyNode.Value
+		recordPositions(&elem, keyNode, valueNode)
 		elements = append(elements, elem)
 	}
 
-	return elements, nil
+	return &node, elements, nil
+}
+
+// recordPositions remembers where the element and each of its attributes
+// were defined, and which attribute keys we didn't recognize
+// (yaml.v3's Decode silently ignores those).
+func recordPositions(elem *Element, keyNode, valueNode *yaml.Node) {
+	elem.Pos = Position{Line: keyNode.Line, Column: keyNode.Column}
+	if valueNode.Kind != yaml.MappingNode {
+		return
+	}
+
+	elem.AttrPos = make(map[string]Position)
+	known := knownAttributes()
+	for i := 0; i < len(valueNode.Content); i += 2 {
+		attrNode := valueNode.Content[i]
+		elem.AttrPos[attrNode.Value] = Position{Line: attrNode.Line, Column: attrNode.Column}
+		if !known[attrNode.Value] {
+			elem.UnknownKeys = append(elem.UnknownKeys, attrNode.Value)
+		}
+		recordEntryPositions(elem, attrNode.Value, valueNode.Content[i+1])
+	}
+}
+
+// recordEntryPositions remembers where each entry of a list attribute,
+// and each key of a map attribute, is (see entryAttr)
+func recordEntryPositions(elem *Element, attr string, node *yaml.Node) {
+	switch node.Kind {
+	case yaml.SequenceNode:
+		for _, entry := range node.Content {
+			elem.AttrPos[entryAttr(attr, entry.Value)] = Position{Line: entry.Line, Column: entry.Column}
+		}
+	case yaml.MappingNode:
+		for i := 0; i < len(node.Content); i += 2 {
+			key := node.Content[i]
+			elem.AttrPos[entryAttr(attr, key.Value)] = Position{Line: key.Line, Column: key.Column}
+		}
+	}
 }
 
 // findGitRoot finds the root directory of the current git repository
@@ -115,6 +180,21 @@ func SaveConfig(cfg *Config) error {
 	var mapNode yaml.Node
 	mapNode.Kind = yaml.MappingNode
 
+	for _, key := range []string{TitleTemplateKey, BodyTemplateKey} {
+		text := cfg.TitleTemplate
+		if key == BodyTemplateKey {
+			text = cfg.BodyTemplate
+		}
+		if text == "" {
+			continue
+		}
+		valueNode := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: text}
+		if strings.Contains(text, "\n") {
+			valueNode.Style = yaml.LiteralStyle
+		}
+		mapNode.Content = append(mapNode.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, valueNode)
+	}
+
 	for _, elem := range cfg.Elements {
 		// Add key node
 		var keyNode yaml.Node
@@ -148,8 +228,7 @@ func SaveConfig(cfg *Config) error {
 func elementToMap(elem Element) map[string]interface{} {
 	m := make(map[string]interface{})
 
-	m["destination"] = string(elem.Destination)
-
+	addStringIfNotEmpty(m, "destination", string(elem.Destination))
 	addStringIfNotEmpty(m, "type", string(elem.Type))
 	addStringIfNotEmpty(m, "instructions", elem.Instructions)
 	addStringIfNotEmpty(m, "before-string", elem.BeforeString)
@@ -157,13 +236,33 @@ func elementToMap(elem Element) map[string]interface{} {
 	addBoolIfNotNil(m, "allow-empty", elem.AllowEmpty)
 	addStringIfNotEmpty(m, "placeholder", elem.Placeholder)
 	addStringIfNotEmpty(m, "data-type", string(elem.DataType))
+	addStringIfNotEmpty(m, "validate-command", elem.ValidateCommand)
+	addIntIfNotZero(m, "validate-timeout", elem.ValidateTimeout)
+	addStringIfNotEmpty(m, "history", string(elem.History))
 	addOptionsIfNotEmpty(m, "options", elem.Options)
 	addBoolIfNotNil(m, "modifiable", elem.Modifiable)
+	addStringIfNotEmpty(m, "modifiable-target", string(elem.ModifiableTarget))
+	addOptionsIfNotEmpty(m, "breaking-options", elem.BreakingOptions)
+	if len(elem.VersionBump) > 0 {
+		m["version-bump"] = elem.VersionBump
+	}
 	addStringIfNotEmpty(m, "record-as", string(elem.RecordAs))
 	addStringIfNotEmpty(m, "bullet-string", elem.BulletString)
 	addStringIfNotEmpty(m, "join-string", elem.JoinString)
 	addIntIfNotZero(m, "limit", elem.Limit)
 	addStringIfNotEmpty(m, "empty-selection-text", elem.EmptySelectionText)
+	addStringIfNotEmpty(m, "order-by", string(elem.OrderBy))
+	addIntIfNotZero(m, "history-depth", elem.HistoryDepth)
+	addStringIfNotEmpty(m, "template", elem.Template)
+	addStringIfNotEmpty(m, "command", elem.Command)
+	addStringIfNotEmpty(m, "ticket-file", elem.TicketFile)
+	addStringIfNotEmpty(m, "ticket-command", elem.TicketCommand)
+	addBoolIfNotNil(m, "record-title", elem.RecordTitle)
+	addStringIfNotEmpty(m, "pattern", elem.Pattern)
+	addBoolIfNotNil(m, "multiple", elem.Multiple)
+	if len(elem.PluginOptions) > 0 {
+		m["plugin-options"] = elem.PluginOptions
+	}
 
 	return m
 }
@@ -193,12 +292,42 @@ func addOptionsIfNotEmpty(m map[string]interface{}, key string, options []string
 }
 
 // AddOptionToElement adds a new option to an element's options list
+// and saves it wherever the element's modifiable-target says to.
+// When the config file is modified only the new option is added to it.
+// Comments, key order, and quoting are left alone.
 func (c *Config) AddOptionToElement(elementName, newOption string) error {
 	for i, elem := range c.Elements {
 		if elem.Name == elementName {
 			c.Elements[i].Options = append(c.Elements[i].Options, newOption)
-			return SaveConfig(c)
+			switch elem.GetModifiableTarget() {
+			case TargetLocal:
+				return addLocalOption(elementName, newOption)
+			case TargetStage:
+				if err := c.saveNewOption(elementName, newOption); err != nil {
+					return err
+				}
+				return stageFile(c.FilePath)
+			default:
+				return c.saveNewOption(elementName, newOption)
+			}
 		}
 	}
 	return errors.New("element not found")
 }
+
+// saveNewOption writes a new option to the config file
+func (c *Config) saveNewOption(elementName, newOption string) error {
+	if c.document == nil {
+		return SaveConfig(c)
+	}
+	return c.appendOptionToDocument(elementName, newOption)
+}
+
+// stageFile runs `git add` on path
+func stageFile(path string) error {
+	cmd := exec.Command("git", "add", "--", path)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git add %s: %s", path, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
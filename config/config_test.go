@@ -2,7 +2,9 @@ package config
 
 import (
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -93,6 +95,25 @@ func TestGetJoinString(t *testing.T) {
 	}
 }
 
+func TestGetOrderBy(t *testing.T) {
+	tests := []struct {
+		name     string
+		elem     Element
+		expected OrderBy
+	}{
+		{"default value", Element{}, OrderByOptions},
+		{"custom value", Element{OrderBy: OrderBySelection}, OrderBySelection},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.elem.GetOrderBy(); got != tt.expected {
+				t.Errorf("GetOrderBy() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
 func TestGetEmptySelectionText(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -133,6 +154,32 @@ func TestHasEmptySelectionText(t *testing.T) {
 	}
 }
 
+func TestIsBreaking(t *testing.T) {
+	selectElem := Element{Type: TypeSelect, Options: []string{"fix", "fix!"}, BreakingOptions: []string{"fix!"}}
+	listElem := Element{Type: TypeMultiSelect, RecordAs: RecordAsList, BreakingOptions: []string{"api"}}
+	tests := []struct {
+		name     string
+		elem     Element
+		value    string
+		expected bool
+	}{
+		{"breaking option", selectElem, "fix!", true},
+		{"other option", selectElem, "fix", false},
+		{"empty value", selectElem, "", false},
+		{"one of several selections", listElem, "\n- docs\n- api\n", true},
+		{"no breaking selection", listElem, "\n- docs\n", false},
+		{"no breaking-options", Element{Type: TypeSelect}, "fix!", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.elem.IsBreaking(tt.value); got != tt.expected {
+				t.Errorf("IsBreaking(%q) = %v, want %v", tt.value, got, tt.expected)
+			}
+		})
+	}
+}
+
 // --- config.go tests ---
 
 func TestLoadConfigFromPath(t *testing.T) {
@@ -590,3 +637,256 @@ func TestElementToMap(t *testing.T) {
 		}
 	})
 }
+
+func TestAddOptionToElement_PreservesFormatting(t *testing.T) {
+	tests := []struct {
+		name     string
+		original string
+		element  string
+		option   string
+		expected string
+	}{
+		{
+			name: "block sequence with comments",
+			original: `# commit types
+commit-type:
+  type: select
+  destination: title
+  options:
+    - fix
+    - feat!  # indicates a breaking change
+
+subject:
+    type: text
+    destination: title
+    before-string: " " # space
+`,
+			element: "commit-type",
+			option:  "docs",
+			expected: `# commit types
+commit-type:
+  type: select
+  destination: title
+  options:
+    - fix
+    - feat!  # indicates a breaking change
+    - docs
+
+subject:
+    type: text
+    destination: title
+    before-string: " " # space
+`,
+		},
+		{
+			name: "quoted options",
+			original: `scope:
+  destination: title
+  type: select
+  options:
+  - 'ui'
+  - 'db'
+`,
+			element: "scope",
+			option:  "api",
+			expected: `scope:
+  destination: title
+  type: select
+  options:
+  - 'ui'
+  - 'db'
+  - 'api'
+`,
+		},
+		{
+			name: "value that needs quoting",
+			original: `scope:
+  destination: title
+  type: select
+  options:
+    - ui
+`,
+			element: "scope",
+			option:  "#1: true",
+			expected: `scope:
+  destination: title
+  type: select
+  options:
+    - ui
+    - '#1: true'
+`,
+		},
+		{
+			name: "no trailing newline",
+			original: `scope:
+  destination: title
+  type: select
+  options:
+    - ui`,
+			element: "scope",
+			option:  "db",
+			expected: `scope:
+  destination: title
+  type: select
+  options:
+    - ui
+    - db
+`,
+		},
+		{
+			name: "flow sequence",
+			original: `scope:
+  destination: title # where it goes
+  type: select
+  options: [ui, db]
+`,
+			element: "scope",
+			option:  "api",
+			expected: `scope:
+  destination: title # where it goes
+  type: select
+  options: [ui, db, api]
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), ".git-com.yml")
+			if err := os.WriteFile(configPath, []byte(tt.original), 0644); err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := LoadConfigFromPath(configPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := cfg.AddOptionToElement(tt.element, tt.option); err != nil {
+				t.Fatalf("AddOptionToElement() error = %v", err)
+			}
+
+			data, err := os.ReadFile(configPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.expected {
+				t.Errorf("file contents =\n%s\nwant\n%s", data, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAddOptionToElement_Twice(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), ".git-com.yaml")
+	original := "scope: # the scope\n  destination: title\n  type: select\n  options:\n    - ui\n"
+	if err := os.WriteFile(configPath, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadConfigFromPath(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, option := range []string{"db", "api"} {
+		if err := cfg.AddOptionToElement("scope", option); err != nil {
+			t.Fatalf("AddOptionToElement(%q) error = %v", option, err)
+		}
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := original + "    - db\n    - api\n"
+	if string(data) != expected {
+		t.Errorf("file contents =\n%s\nwant\n%s", data, expected)
+	}
+}
+
+// initGitRepo creates an empty git repository and makes it the working directory
+func initGitRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Chdir(dir)
+	if output, err := exec.Command("git", "init", "-q").CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v: %s", err, output)
+	}
+	return dir
+}
+
+func TestAddOptionToElement_LocalTarget(t *testing.T) {
+	dir := initGitRepo(t)
+	configPath := filepath.Join(dir, ".git-com.yaml")
+	original := "scope:\n  destination: title\n  type: select\n  modifiable: true\n  modifiable-target: local\n  options:\n    - ui\n"
+	if err := os.WriteFile(configPath, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.AddOptionToElement("scope", "db"); err != nil {
+		t.Fatalf("AddOptionToElement() error = %v", err)
+	}
+
+	// the shared file is untouched
+	data, _ := os.ReadFile(configPath)
+	if string(data) != original {
+		t.Errorf("config file was modified:\n%s", data)
+	}
+
+	// but the option is merged in at load time
+	reloaded, err := LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := reloaded.Elements[0].Options; len(got) != 2 || got[1] != "db" {
+		t.Errorf("Options = %v, want [ui db]", got)
+	}
+
+	// promoting moves it into the shared file
+	promoted, err := reloaded.PromoteLocalOptions()
+	if err != nil {
+		t.Fatalf("PromoteLocalOptions() error = %v", err)
+	}
+	if len(promoted["scope"]) != 1 {
+		t.Errorf("promoted = %v, want scope: [db]", promoted)
+	}
+	data, _ = os.ReadFile(configPath)
+	if string(data) != original+"    - db\n" {
+		t.Errorf("config file after promote =\n%s", data)
+	}
+	local, err := LoadLocalOptions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(local) != 0 {
+		t.Errorf("local options after promote = %v, want none", local)
+	}
+}
+
+func TestAddOptionToElement_StageTarget(t *testing.T) {
+	dir := initGitRepo(t)
+	configPath := filepath.Join(dir, ".git-com.yaml")
+	original := "scope:\n  destination: title\n  type: select\n  modifiable: true\n  modifiable-target: stage\n  options:\n    - ui\n"
+	if err := os.WriteFile(configPath, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.AddOptionToElement("scope", "db"); err != nil {
+		t.Fatalf("AddOptionToElement() error = %v", err)
+	}
+
+	staged, err := exec.Command("git", "diff", "--cached", "--name-only").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(string(staged)) != ".git-com.yaml" {
+		t.Errorf("staged files = %q, want .git-com.yaml", staged)
+	}
+}
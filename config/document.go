@@ -0,0 +1,168 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// appendOptionToDocument adds newOption to the options of elementName in
+// the loaded yaml.Node tree and writes the file back out.
+//
+// When the options are a block sequence we splice a single new line into
+// the original file so everything else stays byte-for-byte identical.
+// Otherwise the edited node tree is re-encoded, which keeps comments, key
+// order, and quoting but may normalize whitespace.
+func (c *Config) appendOptionToDocument(elementName, newOption string) error {
+	root := c.document.Content[0]
+	elemNode := mappingValue(root, elementName)
+	if elemNode == nil || elemNode.Kind != yaml.MappingNode {
+		return errors.New("element not found in " + c.FilePath)
+	}
+
+	newNode := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: newOption}
+	optionsNode := mappingValue(elemNode, "options")
+
+	var data []byte
+	var err error
+	if canSpliceInto(optionsNode) {
+		last := optionsNode.Content[len(optionsNode.Content)-1]
+		// match the quoting of the existing options
+		newNode.Style = last.Style & (yaml.DoubleQuotedStyle | yaml.SingleQuotedStyle)
+		data, err = spliceAfterLine(c.source, last.Line, last.Column, newNode)
+		optionsNode.Content = append(optionsNode.Content, newNode)
+	} else {
+		addToOptionsNode(elemNode, optionsNode, newNode)
+		data, err = encodeDocument(c.document, detectIndent(root))
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(c.FilePath, data, 0644); err != nil {
+		return err
+	}
+	return c.reloadDocument(data)
+}
+
+// reloadDocument re-parses data so that node positions match the new file
+func (c *Config) reloadDocument(data []byte) error {
+	document, _, err := parseDocument(data)
+	if err != nil {
+		return err
+	}
+	c.source = data
+	c.document = document
+	return nil
+}
+
+// mappingValue returns the value node for key in a mapping node, or nil
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// canSpliceInto returns true if options is a block sequence whose last
+// item is a single-line scalar, so a new line can be added right after it
+func canSpliceInto(options *yaml.Node) bool {
+	if options == nil || options.Kind != yaml.SequenceNode || options.Style&yaml.FlowStyle != 0 {
+		return false
+	}
+	if len(options.Content) == 0 {
+		return false
+	}
+	last := options.Content[len(options.Content)-1]
+	if last.Kind != yaml.ScalarNode || last.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+		return false
+	}
+	return !strings.Contains(last.Value, "\n")
+}
+
+// spliceAfterLine inserts a new sequence item after line (1-based).
+// The new line reuses the indentation and "- " of the existing item,
+// which starts at column.
+func spliceAfterLine(source []byte, line, column int, item *yaml.Node) ([]byte, error) {
+	rendered, err := yaml.Marshal(item)
+	if err != nil {
+		return nil, err
+	}
+	rendered = bytes.TrimRight(rendered, "\n")
+
+	lines := bytes.SplitAfter(source, []byte("\n"))
+	if line < 1 || line > len(lines) {
+		return nil, errors.New("could not find options in config file")
+	}
+	existing := lines[line-1]
+	if column-1 > len(existing) {
+		return nil, errors.New("could not find options in config file")
+	}
+
+	newline := []byte("\n")
+	if bytes.HasSuffix(existing, []byte("\r\n")) {
+		newline = []byte("\r\n")
+	}
+
+	var newLine []byte
+	newLine = append(newLine, existing[:column-1]...)
+	newLine = append(newLine, rendered...)
+	newLine = append(newLine, newline...)
+
+	var result []byte
+	for i, l := range lines {
+		result = append(result, l...)
+		if i == line-1 {
+			if !bytes.HasSuffix(l, []byte("\n")) {
+				// the last line of the file had no trailing newline
+				result = append(result, newline...)
+			}
+			result = append(result, newLine...)
+		}
+	}
+	return result, nil
+}
+
+// addToOptionsNode appends item to the options sequence,
+// creating the options key if it doesn't exist yet
+func addToOptionsNode(elemNode, optionsNode, item *yaml.Node) {
+	if optionsNode != nil && optionsNode.Kind == yaml.SequenceNode {
+		optionsNode.Content = append(optionsNode.Content, item)
+		return
+	}
+	key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "options"}
+	seq := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Content: []*yaml.Node{item}}
+	elemNode.Content = append(elemNode.Content, key, seq)
+}
+
+// detectIndent returns the indentation used for element attributes
+func detectIndent(root *yaml.Node) int {
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		if value.Kind == yaml.MappingNode && len(value.Content) > 0 {
+			if indent := value.Content[0].Column - key.Column; indent > 0 {
+				return indent
+			}
+		}
+	}
+	return 2
+}
+
+// encodeDocument serializes a yaml.Node tree using the given indentation
+func encodeDocument(document *yaml.Node, indent int) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(indent)
+	if err := encoder.Encode(document); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
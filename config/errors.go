@@ -0,0 +1,139 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ValidationError describes a single problem with the config file
+type ValidationError struct {
+	File    string
+	Pos     Position
+	Element string // empty for problems with the file as a whole
+	Message string
+}
+
+// Error formats the problem as file:line:column: "element": message
+func (e ValidationError) Error() string {
+	var s strings.Builder
+	s.WriteString(displayPath(e.File))
+	if e.Pos.IsKnown() {
+		fmt.Fprintf(&s, ":%d:%d", e.Pos.Line, e.Pos.Column)
+	}
+	s.WriteString(": ")
+	if e.Element != "" {
+		fmt.Fprintf(&s, "%q: ", e.Element)
+	}
+	s.WriteString(e.Message)
+	return s.String()
+}
+
+// displayPath shortens path relative to the working directory when possible
+func displayPath(path string) string {
+	if path == "" {
+		return ".git-com.y[a]ml"
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	if rel, err := filepath.Rel(wd, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
+
+// attrError is a problem with a specific attribute of an element.
+// The attribute name is used to look up where in the file to point.
+type attrError struct {
+	attr string
+	msg  string
+}
+
+func (e *attrError) Error() string {
+	return e.msg
+}
+
+// attrErrorf creates an attrError for attr with a formatted message
+func attrErrorf(attr, format string, args ...any) error {
+	return &attrError{attr: attr, msg: fmt.Sprintf(format, args...)}
+}
+
+// elementProblems converts the (possibly joined) error returned by
+// validateElement into positioned ValidationErrors
+func elementProblems(file string, elem Element, err error) []ValidationError {
+	var problems []ValidationError
+	for _, e := range flattenErrors(err) {
+		pos := elem.Pos
+		var ae *attrError
+		if errors.As(e, &ae) {
+			pos = elem.AttributePosition(ae.attr)
+		}
+		problems = append(problems, ValidationError{
+			File:    file,
+			Pos:     pos,
+			Element: elem.Name,
+			Message: e.Error(),
+		})
+	}
+	return problems
+}
+
+// flattenErrors unpacks errors created with errors.Join
+func flattenErrors(err error) []error {
+	if err == nil {
+		return nil
+	}
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		return []error{err}
+	}
+	var errs []error
+	for _, e := range joined.Unwrap() {
+		errs = append(errs, flattenErrors(e)...)
+	}
+	return errs
+}
+
+// didYouMean returns ` (did you mean "x"?)` for the closest candidate,
+// or an empty string if nothing is close enough
+func didYouMean(value string, candidates []string) string {
+	best := ""
+	bestDistance := len(value)/3 + 2
+	for _, candidate := range candidates {
+		d := levenshtein(strings.ToLower(value), candidate)
+		if d < bestDistance {
+			best = candidate
+			bestDistance = d
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (did you mean %q?)", best)
+}
+
+// levenshtein returns the edit distance between a and b
+func levenshtein(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+	prev := make([]int, len(br)+1)
+	curr := make([]int, len(br)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ar); i++ {
+		curr[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(br)]
+}
@@ -0,0 +1,145 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// localOptionsFileName is where options added to elements with
+// modifiable-target: local are kept. It lives in the .git directory
+// so it's never committed.
+const localOptionsFileName = "git-com-local-options.yaml"
+
+// LocalOptionsPath returns the path of the local options file
+func LocalOptionsPath() (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--git-path", localOptionsFileName)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", ErrNotInGitRepo
+	}
+	return filepath.Abs(strings.TrimSpace(string(output)))
+}
+
+// LoadLocalOptions returns the locally saved options, keyed by element name
+// A missing file is not an error.
+func LoadLocalOptions() (map[string][]string, error) {
+	path, err := LocalOptionsPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string][]string{}, nil
+		}
+		return nil, err
+	}
+
+	options := map[string][]string{}
+	if err := yaml.Unmarshal(data, &options); err != nil {
+		return nil, errors.New(path + ": " + err.Error())
+	}
+	return options, nil
+}
+
+// saveLocalOptions writes the local options file, removing it when empty
+func saveLocalOptions(options map[string][]string) error {
+	path, err := LocalOptionsPath()
+	if err != nil {
+		return err
+	}
+	if len(options) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	data, err := yaml.Marshal(options)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// addLocalOption saves a new option for elementName to the local options file
+func addLocalOption(elementName, newOption string) error {
+	options, err := LoadLocalOptions()
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(options[elementName], newOption) {
+		options[elementName] = append(options[elementName], newOption)
+	}
+	return saveLocalOptions(options)
+}
+
+// mergeLocalOptions appends locally saved options to their elements
+// Options for elements that no longer exist are ignored.
+func (c *Config) mergeLocalOptions() error {
+	options, err := LoadLocalOptions()
+	if err != nil {
+		return err
+	}
+	for i, elem := range c.Elements {
+		for _, option := range options[elem.Name] {
+			if !slices.Contains(elem.Options, option) {
+				c.Elements[i].Options = append(c.Elements[i].Options, option)
+			}
+		}
+	}
+	return nil
+}
+
+// PromoteLocalOptions moves locally saved options into the config file
+// so the rest of the team gets them. Returns the options that were added,
+// keyed by element name. Options for elements that no longer exist in the
+// config file are left in the local file.
+func (c *Config) PromoteLocalOptions() (map[string][]string, error) {
+	options, err := LoadLocalOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	promoted := map[string][]string{}
+	for _, elem := range c.Elements {
+		for _, option := range options[elem.Name] {
+			if !c.fileHasOption(elem.Name, option) {
+				if err := c.saveNewOption(elem.Name, option); err != nil {
+					return promoted, err
+				}
+			}
+			promoted[elem.Name] = append(promoted[elem.Name], option)
+		}
+		delete(options, elem.Name)
+	}
+
+	return promoted, saveLocalOptions(options)
+}
+
+// fileHasOption returns true if the config file itself (as opposed to the
+// merged in local options) lists option for elementName
+func (c *Config) fileHasOption(elementName, option string) bool {
+	if c.document == nil {
+		return false
+	}
+	elemNode := mappingValue(c.document.Content[0], elementName)
+	if elemNode == nil {
+		return false
+	}
+	optionsNode := mappingValue(elemNode, "options")
+	if optionsNode == nil {
+		return false
+	}
+	for _, item := range optionsNode.Content {
+		if item.Value == option {
+			return true
+		}
+	}
+	return false
+}
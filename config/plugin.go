@@ -0,0 +1,120 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// PluginPrefix starts the name of the executable that handles a custom
+// element type: type: issue is handled by git-com-element-issue on PATH
+const PluginPrefix = "git-com-element-"
+
+// PluginProtocolVersion is sent to plugins so the request can change
+// without breaking the ones already written
+const PluginProtocolVersion = 1
+
+// PluginExitAborted is the exit status plugins use when the user cancels
+const PluginExitAborted = 130
+
+// ErrPluginAborted is returned when a plugin says the user cancelled
+var ErrPluginAborted = errors.New("plugin aborted")
+
+// pluginTypePattern is what a plugin type's name must look like. It
+// keeps the names the schema can accept the same as the ones that are
+// looked up, and stops a type like ../bin/x being run as a path.
+const pluginTypePattern = "^[a-z0-9][a-z0-9-]*$"
+
+var pluginTypeRegexp = regexp.MustCompile(pluginTypePattern)
+
+// IsBuiltinType returns true if elemType is handled by git-com itself
+func IsBuiltinType(elemType ElementType) bool {
+	return slices.Contains(validTypes, string(elemType))
+}
+
+// FindPlugin returns the path of the plugin for elemType, if one is on PATH
+func FindPlugin(elemType ElementType) (string, bool) {
+	if !pluginTypeRegexp.MatchString(string(elemType)) || IsBuiltinType(elemType) {
+		return "", false
+	}
+	path, err := exec.LookPath(PluginPrefix + string(elemType))
+	if err != nil {
+		return "", false
+	}
+	return path, true
+}
+
+// PluginRequest is sent to a plugin as JSON on stdin
+type PluginRequest struct {
+	Protocol int               `json:"protocol"`
+	Name     string            `json:"name"`    // the element's name
+	Element  map[string]any    `json:"element"` // its attributes, as written in the config
+	Default  string            `json:"default"` // the current value when rewording, otherwise ""
+	Values   map[string]string `json:"values"`  // earlier elements' values, by name
+}
+
+// PluginResponse is what a plugin prints on stdout: either the value,
+// or options for git-com to offer. Output that isn't a JSON object is
+// taken to be the value.
+type PluginResponse struct {
+	Value    *string  `json:"value,omitempty"`
+	Options  []string `json:"options,omitempty"`
+	Multiple bool     `json:"multiple,omitempty"` // more than one option may be selected
+}
+
+// RunPlugin runs the plugin at path for elem. The plugin's stderr is the
+// terminal's, and it can open /dev/tty to run its own interface.
+func RunPlugin(path string, elem Element, defaultValue string, values map[string]string) (*PluginResponse, error) {
+	if values == nil {
+		values = map[string]string{}
+	}
+	request, err := json.Marshal(PluginRequest{
+		Protocol: PluginProtocolVersion,
+		Name:     elem.Name,
+		Element:  elementToMap(elem),
+		Default:  defaultValue,
+		Values:   values,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var stdout bytes.Buffer
+	cmd := exec.Command(path)
+	cmd.Stdin = bytes.NewReader(request)
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == PluginExitAborted {
+			return nil, ErrPluginAborted
+		}
+		return nil, fmt.Errorf("%s: %w", PluginPrefix+string(elem.Type), err)
+	}
+
+	return parsePluginResponse(stdout.Bytes())
+}
+
+// parsePluginResponse reads a plugin's output
+func parsePluginResponse(output []byte) (*PluginResponse, error) {
+	trimmed := bytes.TrimSpace(output)
+	if !bytes.HasPrefix(trimmed, []byte("{")) {
+		value := strings.TrimSpace(string(trimmed))
+		return &PluginResponse{Value: &value}, nil
+	}
+
+	var response PluginResponse
+	if err := json.Unmarshal(trimmed, &response); err != nil {
+		return nil, fmt.Errorf("couldn't read the plugin's response: %w", err)
+	}
+	if response.Value == nil && len(response.Options) == 0 {
+		return nil, errors.New("the plugin's response has neither a value nor options")
+	}
+	return &response, nil
+}
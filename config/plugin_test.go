@@ -0,0 +1,114 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// installPlugin puts a git-com-element-<name> script on PATH
+func installPlugin(t *testing.T, name, script string) string {
+	t.Helper()
+	dir := t.TempDir()
+	path := filepath.Join(dir, PluginPrefix+name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return path
+}
+
+func TestFindPlugin(t *testing.T) {
+	want := installPlugin(t, "issue", "echo ABC-1")
+
+	if path, ok := FindPlugin("issue"); !ok || path != want {
+		t.Errorf("FindPlugin(issue) = %q, %v, want %q", path, ok, want)
+	}
+	if _, ok := FindPlugin("missing"); ok {
+		t.Error("FindPlugin(missing) found a plugin")
+	}
+	if _, ok := FindPlugin("../issue"); ok {
+		t.Error("FindPlugin(../issue) found a plugin for a type that isn't a plugin name")
+	}
+	if _, ok := FindPlugin(TypeText); ok {
+		t.Error("FindPlugin(text) found a plugin for a built in type")
+	}
+}
+
+func TestValidateElement_PluginTypes(t *testing.T) {
+	installPlugin(t, "issue", "echo ABC-1")
+
+	plugin := Element{Destination: DestBody, Type: "issue", PluginOptions: map[string]any{"db": "issues.sqlite"}}
+	if err := validateElement(plugin); err != nil {
+		t.Errorf("validateElement(plugin type) error = %v", err)
+	}
+	if err := validateElement(Element{Type: "issue"}); err == nil {
+		t.Error("validateElement(plugin type without destination) expected an error")
+	}
+	if err := validateElement(Element{Destination: DestBody, Type: "ticket"}); err == nil {
+		t.Error("validateElement(type without a plugin) expected an error")
+	}
+}
+
+func TestRunPlugin(t *testing.T) {
+	// keep the request to check what was sent
+	requestFile := filepath.Join(t.TempDir(), "request.json")
+	path := installPlugin(t, "issue", "cat > "+requestFile+"\necho ABC-2")
+	elem := Element{Name: "ref", Destination: DestBody, Type: "issue", PluginOptions: map[string]any{"db": "issues.sqlite"}}
+
+	response, err := RunPlugin(path, elem, "ABC-1", map[string]string{"subject": "fix it"})
+	if err != nil {
+		t.Fatalf("RunPlugin() error = %v", err)
+	}
+	if response.Value == nil || *response.Value != "ABC-2" {
+		t.Errorf("RunPlugin() = %+v, want the value ABC-2", response)
+	}
+
+	data, err := os.ReadFile(requestFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var request PluginRequest
+	if err := json.Unmarshal(data, &request); err != nil {
+		t.Fatalf("request isn't JSON: %v\n%s", err, data)
+	}
+	options, _ := request.Element["plugin-options"].(map[string]any)
+	if request.Protocol != PluginProtocolVersion || request.Name != "ref" || request.Default != "ABC-1" ||
+		request.Values["subject"] != "fix it" || request.Element["type"] != "issue" || options["db"] != "issues.sqlite" {
+		t.Errorf("request = %s", data)
+	}
+}
+
+func TestParsePluginResponse(t *testing.T) {
+	response, err := parsePluginResponse([]byte("ABC-1\n"))
+	if err != nil || response.Value == nil || *response.Value != "ABC-1" {
+		t.Errorf("plain output = %+v, %v", response, err)
+	}
+
+	response, err = parsePluginResponse([]byte(`{"value": "ABC-2"}`))
+	if err != nil || response.Value == nil || *response.Value != "ABC-2" {
+		t.Errorf("value response = %+v, %v", response, err)
+	}
+
+	response, err = parsePluginResponse([]byte(`{"options": ["ABC-1", "ABC-2"], "multiple": true}`))
+	if err != nil || len(response.Options) != 2 || !response.Multiple {
+		t.Errorf("options response = %+v, %v", response, err)
+	}
+
+	if _, err := parsePluginResponse([]byte(`{}`)); err == nil {
+		t.Error("empty response expected an error")
+	}
+	if _, err := parsePluginResponse([]byte(`{"value": `)); err == nil {
+		t.Error("broken JSON expected an error")
+	}
+}
+
+func TestRunPlugin_Aborted(t *testing.T) {
+	path := installPlugin(t, "issue", "exit 130")
+	_, err := RunPlugin(path, Element{Name: "ref", Type: "issue"}, "", nil)
+	if !errors.Is(err, ErrPluginAborted) {
+		t.Errorf("RunPlugin() error = %v, want ErrPluginAborted", err)
+	}
+}
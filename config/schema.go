@@ -0,0 +1,313 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// attributeDescriptions are shown by editors that understand JSON Schema
+var attributeDescriptions = map[string]string{
+	"destination":          "Where the input goes: title or body",
+	"type":                 "The element type. Can be omitted if data-type is specified, in which case text is assumed. Plugin types depend on what's installed, so they aren't listed.",
+	"instructions":         "Text displayed above the input prompt",
+	"before-string":        "Text prepended to the user's input",
+	"after-string":         "Text appended to the user's input",
+	"allow-empty":          "Whether empty input is accepted",
+	"placeholder":          "Grayed-out hint text shown in empty input",
+	"data-type":            "Validation type for text input",
+	"validate-command":     "Shell command given a typed-in value (or an option added with \"Other…\") on stdin; a non-zero exit rejects it and its stderr is shown",
+	"validate-timeout":     "Seconds validate-command may run for before the value is rejected",
+	"history":              "Where suggestions come from: mine (your commits), all (everyone's), or off",
+	"options":              "List of choices",
+	"modifiable":           "Allow users to add new options (saved to config file)",
+	"modifiable-target":    "Where options added with \"Other…\" are saved: repo-file, local (under .git/), or stage (repo-file, then staged)",
+	"record-as":            "Output format of a multi-select",
+	"bullet-string":        "Prefix for each item when record-as: list",
+	"join-string":          "Separator when record-as: joined-string",
+	"limit":                "Maximum number of selections (0 = unlimited)",
+	"empty-selection-text": "Label for the \"skip\" option (requires allow-empty: true)",
+	"order-by":             "Order a multi-select's selections are recorded in: selection (the order they were chosen), options, or alpha",
+	"breaking-options":     "Options (from options) that mark a commit as a breaking change in changelogs",
+	"version-bump":         "Maps options to the major, minor, or patch version bump they call for",
+	"template":             "Go text/template a computed element's value comes from",
+	"command":              "Shell command whose output is a computed element's value",
+	"ticket-file":          "JSON or CSV file of ticket ids and titles, relative to the config file",
+	"ticket-command":       "Shell command that prints ticket ids and titles as JSON or CSV",
+	"record-title":         "Record a ticket's title after its id",
+	"pattern":              "Regular expression tickets that aren't listed must match to be entered",
+	"multiple":             "Allow choosing more than one ticket",
+	"plugin-options":       "Settings passed as they are to the plugin (git-com-element-<type>) a custom type is handled by",
+	"history-depth":        "How many recent commits co-authors and text suggestions are found in (default 500)",
+}
+
+// attributeEnums are the allowed values for attributes that apply to every type.
+// Type specific values live in typeSchema.
+var attributeEnums = map[string][]string{
+	"modifiable-target": validTargets,
+	"version-bump":      validVersionBumps,
+}
+
+// Schema returns a JSON Schema (draft 2020-12) describing the config file.
+// It's generated from Element, typeRules, and the enumerations in
+// validation.go so that it stays in sync with ValidateConfig.
+func Schema() map[string]any {
+	return map[string]any{
+		"$schema":     "https://json-schema.org/draft/2020-12/schema",
+		"title":       "git-com configuration",
+		"description": "Each top-level key is an element, apart from title-template and body-template. Elements are prompted for in order. At least one element must have destination: title.",
+		"type":        "object",
+		"properties": map[string]any{
+			TitleTemplateKey: map[string]any{"type": "string", "description": "Go text/template the title is rendered with, given every element's value by name"},
+			BodyTemplateKey:  map[string]any{"type": "string", "description": "Go text/template the body is rendered with, given every element's value by name"},
+		},
+		"additionalProperties": map[string]any{"$ref": "#/$defs/element"},
+		"$defs": map[string]any{
+			"element": elementSchema(),
+		},
+	}
+}
+
+// SchemaJSON returns Schema() as indented JSON
+func SchemaJSON() ([]byte, error) {
+	return json.MarshalIndent(Schema(), "", "  ")
+}
+
+// elementSchema describes a single element
+func elementSchema() map[string]any {
+	rules := []any{
+		// see validateElement: "missing type"
+		map[string]any{"anyOf": []any{
+			map[string]any{"required": []string{"type"}},
+			map[string]any{"required": []string{"data-type"}},
+		}},
+		// see validateTitleConstraints
+		map[string]any{
+			"if":   map[string]any{"properties": map[string]any{"destination": map[string]any{"const": string(DestTitle)}}, "required": []string{"destination"}},
+			"then": map[string]any{"properties": map[string]any{"before-string": noNewlines(), "after-string": noNewlines()}},
+		},
+	}
+	for _, t := range validTypes {
+		elemType := ElementType(t)
+		rules = append(rules, map[string]any{
+			"if":   typeCondition(elemType),
+			"then": typeSchema(elemType),
+		})
+	}
+	rules = append(rules, map[string]any{
+		"if":   pluginCondition(),
+		"then": pluginSchema(),
+	})
+
+	return map[string]any{
+		"type":                 "object",
+		"properties":           attributeProperties(),
+		"additionalProperties": false,
+		"allOf":                rules,
+	}
+}
+
+// attributeProperties describes every attribute from Element's yaml tags
+func attributeProperties() map[string]any {
+	properties := make(map[string]any)
+	t := reflect.TypeOf(Element{})
+	for i := 0; i < t.NumField(); i++ {
+		name := attributeName(t.Field(i))
+		if name == "" {
+			continue
+		}
+		property := jsonType(t.Field(i).Type)
+		property["description"] = attributeDescriptions[name]
+		if enum, ok := attributeEnums[name]; ok {
+			if values, isMap := property["additionalProperties"].(map[string]any); isMap {
+				// the values of a map are enumerated, not the map itself
+				values["enum"] = enum
+			} else {
+				property["enum"] = enum
+			}
+		}
+		properties[name] = property
+	}
+	// plugin types depend on what's installed, so any name a plugin could
+	// have is allowed, with the built in types listed for editors to suggest
+	properties["type"].(map[string]any)["anyOf"] = []any{
+		map[string]any{"enum": validTypes},
+		map[string]any{"pattern": pluginTypePattern},
+	}
+	return properties
+}
+
+// jsonType maps a Go field type to a JSON Schema type
+func jsonType(t reflect.Type) map[string]any {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int:
+		return map[string]any{"type": "integer", "minimum": 0}
+	case reflect.Slice:
+		return map[string]any{"type": "array", "items": jsonType(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": jsonType(t.Elem())}
+	case reflect.Interface:
+		// anything at all
+		return map[string]any{}
+	default:
+		return map[string]any{"type": "string"}
+	}
+}
+
+// typeCondition matches elements of elemType (see GetEffectiveType)
+func typeCondition(elemType ElementType) map[string]any {
+	explicit := map[string]any{
+		"properties": map[string]any{"type": map[string]any{"const": string(elemType)}},
+		"required":   []string{"type"},
+	}
+	if elemType != TypeText {
+		return explicit
+	}
+	// text is inferred when type is missing
+	return map[string]any{"anyOf": []any{
+		explicit,
+		map[string]any{"not": map[string]any{"required": []string{"type"}}},
+	}}
+}
+
+// pluginCondition matches elements whose type is handled by a plugin
+func pluginCondition() map[string]any {
+	return map[string]any{
+		"properties": map[string]any{"type": map[string]any{"not": map[string]any{"enum": validTypes}}},
+		"required":   []string{"type"},
+	}
+}
+
+// pluginSchema describes the rules for plugin types. Whether the plugin
+// is installed can't be known here, so it's assumed to be.
+func pluginSchema() map[string]any {
+	return map[string]any{
+		"required":   []string{"destination"},
+		"properties": map[string]any{"destination": map[string]any{"enum": validDestinations}},
+		// see validateTextOnly, validateValidateCommand, validateBreakingOptions,
+		// and validateVersionBump
+		"allOf": append(textOnlyRules(),
+			map[string]any{"not": map[string]any{"required": []string{"validate-command"}}},
+			map[string]any{"not": map[string]any{"required": []string{"breaking-options"}}},
+			map[string]any{"not": map[string]any{"required": []string{"version-bump"}}},
+		),
+	}
+}
+
+// typeSchema describes the rules for a single element type
+// It mirrors validateRequired, validateDestination, and validateByType
+func typeSchema(elemType ElementType) map[string]any {
+	rule := typeRules[elemType]
+	required := []string{}
+	properties := map[string]any{}
+
+	if rule.destination {
+		required = append(required, "destination")
+		properties["destination"] = map[string]any{"enum": validDestinations}
+		if len(rule.destinations) > 0 {
+			allowed := make([]string, len(rule.destinations))
+			for i, dest := range rule.destinations {
+				allowed[i] = string(dest)
+			}
+			properties["destination"] = map[string]any{"enum": allowed}
+		}
+	} else {
+		properties["destination"] = map[string]any{"const": ""}
+	}
+	for _, attr := range rule.required {
+		required = append(required, attr)
+		if value, _ := attributeValue(Element{}, attr); value.Kind() == reflect.Slice {
+			properties[attr] = map[string]any{"minItems": 1}
+		}
+	}
+
+	schema := map[string]any{"required": required, "properties": properties}
+	rules := []any{
+		// see validatePluginOptions
+		map[string]any{"not": map[string]any{"required": []string{"plugin-options"}}},
+	}
+	if elemType != TypeText {
+		// see validateTextOnly
+		rules = append(rules, textOnlyRules()...)
+	}
+	if elemType != TypeSelect && elemType != TypeMultiSelect {
+		// see validateBreakingOptions and validateVersionBump. That they
+		// name some of the options can't be said in JSON Schema, so only
+		// validation checks it.
+		rules = append(rules,
+			map[string]any{"not": map[string]any{"required": []string{"breaking-options"}}},
+			map[string]any{"not": map[string]any{"required": []string{"version-bump"}}},
+		)
+	}
+	// see validateValidateCommand
+	switch elemType {
+	case TypeText, TypeMultilineText:
+		// anything typed in can be checked
+	case TypeSelect, TypeMultiSelect:
+		rules = append(rules, map[string]any{
+			"if":   map[string]any{"required": []string{"validate-command"}},
+			"then": map[string]any{"properties": map[string]any{"modifiable": map[string]any{"const": true}}, "required": []string{"modifiable"}},
+		})
+	default:
+		rules = append(rules, map[string]any{"not": map[string]any{"required": []string{"validate-command"}}})
+	}
+
+	switch elemType {
+	case TypeComputed:
+		// see validateComputedElement
+		rules = append(rules, oneOf("template", "command")...)
+	case TypeTicket:
+		// see validateTicketElement
+		rules = append(rules, oneOf("ticket-file", "ticket-command")...)
+	case TypeText:
+		// see validateTextElement
+		properties["data-type"] = map[string]any{"enum": validDataTypes}
+		properties["history"] = map[string]any{"enum": validHistory}
+	case TypeMultiSelect:
+		// see validateMultiSelectElement
+		properties["record-as"] = map[string]any{"enum": validRecordAs}
+		properties["order-by"] = map[string]any{"enum": validOrderBy}
+		rules = append(rules,
+			map[string]any{
+				"if":   map[string]any{"properties": map[string]any{"destination": map[string]any{"const": string(DestTitle)}}, "required": []string{"destination"}},
+				"then": map[string]any{"properties": map[string]any{"record-as": map[string]any{"const": string(RecordAsJoinedString)}}},
+			},
+			map[string]any{
+				"if":   map[string]any{"properties": map[string]any{"empty-selection-text": map[string]any{"minLength": 1}}, "required": []string{"empty-selection-text"}},
+				"then": map[string]any{"properties": map[string]any{"allow-empty": map[string]any{"const": true}}, "required": []string{"allow-empty"}},
+			},
+		)
+	}
+
+	schema["allOf"] = rules
+	return schema
+}
+
+// textOnlyRules forbid textOnlyAttributes
+func textOnlyRules() []any {
+	var rules []any
+	for _, attr := range textOnlyAttributes {
+		rules = append(rules, map[string]any{"not": map[string]any{"required": []string{attr}}})
+	}
+	return rules
+}
+
+// oneOf requires exactly one of the attributes a and b (see validateOneOf)
+func oneOf(a, b string) []any {
+	return []any{
+		map[string]any{"anyOf": []any{
+			map[string]any{"required": []string{a}},
+			map[string]any{"required": []string{b}},
+		}},
+		map[string]any{"not": map[string]any{"required": []string{a, b}}},
+	}
+}
+
+// noNewlines is a string that can't contain newlines
+func noNewlines() map[string]any {
+	return map[string]any{"pattern": "^[^\\n]*$"}
+}
@@ -0,0 +1,298 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestSchemaCoversEveryAttribute(t *testing.T) {
+	properties := attributeProperties()
+	for _, name := range attributeNames() {
+		if _, ok := properties[name]; !ok {
+			t.Errorf("schema is missing attribute %q", name)
+		}
+		if attributeDescriptions[name] == "" {
+			t.Errorf("attribute %q has no description", name)
+		}
+	}
+	if len(properties) != len(attributeNames()) {
+		t.Errorf("schema has %d properties, Element has %d attributes", len(properties), len(attributeNames()))
+	}
+}
+
+func TestSchemaJSON(t *testing.T) {
+	data, err := SchemaJSON()
+	if err != nil {
+		t.Fatalf("SchemaJSON() error = %v", err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("SchemaJSON() produced invalid JSON: %v", err)
+	}
+}
+
+// TestSchemaAgreesWithValidation checks that the schema accepts exactly
+// the elements that validateElement accepts.
+func TestSchemaAgreesWithValidation(t *testing.T) {
+	corpus := []string{
+		"destination: title\ntype: text",
+		"destination: body\ntype: text\nbefore-string: \"\\n\\nTicket: \"",
+		"destination: title\ntype: text\nbefore-string: \"\\n\"",
+		"destination: title\ntype: text\nafter-string: \"a\\nb\"",
+		"destination: titel\ntype: text",
+		"type: text",
+		"destination: title",
+		"destination: body\ndata-type: integer",
+		"destination: body\ndata-type: boolean",
+		"destination: body\ntype: text\ndata-type: float",
+		"destination: title\ntype: txt",
+		"destination: title\ntype: Text",
+		"destination: title\ntype: ../bin/text",
+		"destination: title\ntype: text\nplacholder: x",
+		"destination: body\ntype: multiline-text\nplaceholder: x",
+		"destination: title\ntype: select\noptions: [a, b]",
+		"destination: title\ntype: select",
+		"destination: title\ntype: select\noptions: []",
+		"destination: title\ntype: select\noptions: [a]\nmodifiable: true",
+		"destination: title\ntype: select\noptions: [a]\nmodifiable: true\nmodifiable-target: local",
+		"destination: title\ntype: select\noptions: [a]\nmodifiable: true\nmodifiable-target: stage",
+		"destination: title\ntype: select\noptions: [a]\nmodifiable: true\nmodifiable-target: locale",
+		"destination: body\ntype: multi-select\noptions: [a]\nrecord-as: list",
+		"destination: body\ntype: multi-select\noptions: [a]",
+		"destination: body\ntype: multi-select\nrecord-as: list",
+		"destination: body\ntype: multi-select\noptions: [a]\nrecord-as: csv",
+		"destination: title\ntype: multi-select\noptions: [a]\nrecord-as: list",
+		"destination: title\ntype: multi-select\noptions: [a]\nrecord-as: joined-string",
+		"destination: body\ntype: multi-select\noptions: [a]\nrecord-as: list\nempty-selection-text: Skip",
+		"destination: body\ntype: multi-select\noptions: [a]\nrecord-as: list\nempty-selection-text: Skip\nallow-empty: false",
+		"destination: body\ntype: multi-select\noptions: [a]\nrecord-as: list\nempty-selection-text: Skip\nallow-empty: true",
+		"type: confirmation",
+		"type: confirmation\ninstructions: Sure?",
+		"type: confirmation\ndestination: title",
+		"type: confirmation\ndestination: \"\"",
+		"destination: title\ntype: select\noptions: [feat, feat!]\nbreaking-options: [feat!]",
+		"destination: body\ntype: multi-select\noptions: [a, breaking]\nrecord-as: list\nbreaking-options: [breaking]",
+		"destination: title\ntype: text\nbreaking-options: [feat!]",
+		"type: confirmation\nbreaking-options: [feat!]",
+		"destination: title\ntype: sqlite-issue\nbreaking-options: [feat!]",
+		"destination: title\ntype: select\noptions: [feat, fix]\nversion-bump: {feat: minor, fix: patch}",
+		"destination: title\ntype: select\noptions: [feat, fix]\nversion-bump: {feat: minr}",
+		"destination: title\ntype: text\nversion-bump: {feat: minor}",
+		"destination: title\ntype: sqlite-issue\nversion-bump: {feat: minor}",
+		"destination: body\ntype: co-authors",
+		"destination: body\ntype: co-authors\nhistory-depth: 50\nallow-empty: true",
+		"destination: title\ntype: co-authors",
+		"type: co-authors",
+		"destination: body\ntype: computed\ntemplate: '{{branch}}'",
+		"destination: body\ntype: computed\ncommand: git rev-parse HEAD\nallow-empty: true",
+		"destination: body\ntype: computed",
+		"destination: body\ntype: computed\ntemplate: x\ncommand: echo x",
+		"type: computed\ncommand: echo x",
+		"destination: title\ntype: text\nvalidate-command: grep -q x\nvalidate-timeout: 10",
+		"destination: title\ntype: text\nvalidate-command: grep -q x\nvalidate-timeout: -5",
+		"destination: body\ntype: multi-select\noptions: [a]\nrecord-as: list\nlimit: -1",
+		"destination: body\ntype: co-authors\nhistory-depth: -1",
+		"destination: title\ndata-type: integer\nvalidate-command: grep -q 1",
+		"destination: title\ntype: select\noptions: [a]\nvalidate-command: grep -q a",
+		"destination: title\ntype: select\noptions: [a]\nmodifiable: true\nvalidate-command: grep -q a",
+		"destination: title\ntype: select\noptions: [a]\nmodifiable: false\nvalidate-command: grep -q a",
+		"destination: body\ntype: multi-select\noptions: [a]\nrecord-as: list\nmodifiable: true\nvalidate-command: grep -q a",
+		"destination: body\ntype: multiline-text\nvalidate-command: grep -q a",
+		"destination: title\ntype: sqlite-issue\nvalidate-command: grep -q a",
+		"destination: body\ntype: co-authors\nvalidate-command: grep -q a",
+		"type: confirmation\nvalidate-command: grep -q a",
+		"destination: body\ntype: computed\ncommand: echo x\nvalidate-command: grep -q x",
+		"destination: title\ntype: select\noptions: [a]\nplugin-options: {db: issues.sqlite}",
+		"destination: title\ntype: text\nplugin-options: {}",
+		"destination: title\ntype: sqlite-issue",
+		"destination: title\ntype: sqlite-issue\nplugin-options: {db: issues.sqlite}",
+		"type: sqlite-issue",
+		"destination: subject\ntype: sqlite-issue",
+		"destination: title\ntype: sqlite-issue\nhistory: all",
+		"destination: body\ntype: ticket\nticket-file: tickets.csv",
+		"destination: body\ntype: ticket\nticket-command: cat tickets.json\nrecord-title: true\nmultiple: true\npattern: '[A-Z]+-[0-9]+'",
+		"destination: body\ntype: ticket",
+		"destination: body\ntype: ticket\nticket-file: tickets.csv\nticket-command: cat tickets.csv",
+		"type: ticket\nticket-file: tickets.csv",
+		"destination: title\ntype: text\nhistory: off\nhistory-depth: 50",
+		"destination: title\ntype: text\nhistory: everyone",
+		"destination: title\ntype: multiline-text\nhistory: all",
+		"destination: title\ntype: multi-select\noptions: [a, b]\nrecord-as: joined-string\norder-by: selection",
+		"destination: title\ntype: multi-select\noptions: [a, b]\nrecord-as: joined-string\norder-by: picked",
+	}
+
+	// the schema can't see PATH, so it takes every type that could be a
+	// plugin to be one
+	installPlugin(t, "sqlite-issue", "echo 1")
+	installPlugin(t, "txt", "echo 1")
+
+	schema := Schema()
+	for _, source := range corpus {
+		t.Run(strings.ReplaceAll(source, "\n", "; "), func(t *testing.T) {
+			elements, err := parseOrderedYAML([]byte("elem:\n  " + strings.ReplaceAll(source, "\n", "\n  ")))
+			if err != nil {
+				t.Fatalf("parseOrderedYAML() error = %v", err)
+			}
+			validationErr := validateElement(elements[0])
+
+			var doc map[string]any
+			if err := yaml.Unmarshal([]byte(source), &doc); err != nil {
+				t.Fatal(err)
+			}
+			schemaErr := checkSchema(schema, schema["$defs"].(map[string]any)["element"], doc)
+
+			if (validationErr == nil) != (schemaErr == nil) {
+				t.Errorf("validation error = %v, schema error = %v", validationErr, schemaErr)
+			}
+		})
+	}
+}
+
+// checkSchema is a minimal JSON Schema evaluator covering the keywords Schema uses
+func checkSchema(root map[string]any, s any, value any) error {
+	schema := s.(map[string]any)
+	keys := make([]string, 0, len(schema))
+	for k := range schema {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, keyword := range keys {
+		arg := schema[keyword]
+		var err error
+		switch keyword {
+		case "$ref":
+			name := strings.TrimPrefix(arg.(string), "#/$defs/")
+			err = checkSchema(root, root["$defs"].(map[string]any)[name], value)
+		case "type":
+			err = checkType(arg.(string), value)
+		case "enum":
+			if !containsValue(arg, value) {
+				err = fmt.Errorf("%v is not one of %v", value, arg)
+			}
+		case "const":
+			if fmt.Sprint(arg) != fmt.Sprint(value) {
+				err = fmt.Errorf("%v is not %v", value, arg)
+			}
+		case "pattern":
+			if str, ok := value.(string); ok && !regexp.MustCompile(arg.(string)).MatchString(str) {
+				err = fmt.Errorf("%q does not match %s", str, arg)
+			}
+		case "minLength":
+			if str, ok := value.(string); ok && len(str) < arg.(int) {
+				err = fmt.Errorf("%q is too short", str)
+			}
+		case "minItems":
+			if list, ok := value.([]any); ok && len(list) < arg.(int) {
+				err = fmt.Errorf("not enough items")
+			}
+		case "minimum":
+			if n, ok := value.(int); ok && n < arg.(int) {
+				err = fmt.Errorf("%d is too small", n)
+			}
+		case "items":
+			for _, item := range value.([]any) {
+				if err = checkSchema(root, arg, item); err != nil {
+					break
+				}
+			}
+		case "required":
+			if obj, ok := value.(map[string]any); ok {
+				for _, name := range arg.([]string) {
+					if _, present := obj[name]; !present {
+						err = fmt.Errorf("missing %s", name)
+						break
+					}
+				}
+			}
+		case "properties":
+			if obj, ok := value.(map[string]any); ok {
+				for name, sub := range arg.(map[string]any) {
+					if v, present := obj[name]; present {
+						if err = checkSchema(root, sub, v); err != nil {
+							break
+						}
+					}
+				}
+			}
+		case "additionalProperties":
+			properties, _ := schema["properties"].(map[string]any)
+			for name, v := range value.(map[string]any) {
+				if _, known := properties[name]; known {
+					continue
+				}
+				if arg == false {
+					err = fmt.Errorf("unknown property %s", name)
+				} else {
+					err = checkSchema(root, arg, v)
+				}
+				if err != nil {
+					break
+				}
+			}
+		case "allOf":
+			for _, sub := range arg.([]any) {
+				if err = checkSchema(root, sub, value); err != nil {
+					break
+				}
+			}
+		case "anyOf":
+			err = fmt.Errorf("matches none of anyOf")
+			for _, sub := range arg.([]any) {
+				if checkSchema(root, sub, value) == nil {
+					err = nil
+					break
+				}
+			}
+		case "not":
+			if checkSchema(root, arg, value) == nil {
+				err = fmt.Errorf("matches not")
+			}
+		case "if":
+			if checkSchema(root, arg, value) == nil {
+				if then, ok := schema["then"]; ok {
+					err = checkSchema(root, then, value)
+				}
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkType(expected string, value any) error {
+	ok := false
+	switch expected {
+	case "object":
+		_, ok = value.(map[string]any)
+	case "string":
+		_, ok = value.(string)
+	case "boolean":
+		_, ok = value.(bool)
+	case "integer":
+		_, ok = value.(int)
+	case "array":
+		_, ok = value.([]any)
+	}
+	if !ok {
+		return fmt.Errorf("%v is not a %s", value, expected)
+	}
+	return nil
+}
+
+func containsValue(list any, value any) bool {
+	for _, item := range list.([]string) {
+		if item == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,201 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Top-level keys for templates. They can't be used as element names.
+const (
+	TitleTemplateKey = "title-template"
+	BodyTemplateKey  = "body-template"
+)
+
+// isTemplateKey returns true if a top-level key is a template, not an element
+func isTemplateKey(key string) bool {
+	return key == TitleTemplateKey || key == BodyTemplateKey
+}
+
+// TemplateFuncs are the helpers available in title-template and body-template
+var TemplateFuncs = template.FuncMap{
+	"join":    templateJoin,
+	"upper":   strings.ToUpper,
+	"lower":   strings.ToLower,
+	"trim":    strings.TrimSpace,
+	"default": templateDefault,
+	"wrap":    templateWrap,
+}
+
+// templateJoin joins a multi-select's selections: {{join ", " .tags}}
+func templateJoin(sep string, value any) string {
+	switch v := value.(type) {
+	case []string:
+		return strings.Join(v, sep)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// templateDefault returns fallback when value is empty: {{default "misc" .scope}}
+func templateDefault(fallback string, value any) any {
+	switch v := value.(type) {
+	case string:
+		if v == "" {
+			return fallback
+		}
+	case []string:
+		if len(v) == 0 {
+			return fallback
+		}
+	case nil:
+		return fallback
+	}
+	return value
+}
+
+// templateWrap wraps text at width columns, keeping existing line breaks:
+// {{wrap 72 .description}}
+func templateWrap(width int, text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		var wrapped strings.Builder
+		length := 0
+		for _, word := range strings.Fields(line) {
+			if length > 0 && length+1+len([]rune(word)) > width {
+				wrapped.WriteString("\n")
+				length = 0
+			} else if length > 0 {
+				wrapped.WriteString(" ")
+				length++
+			}
+			wrapped.WriteString(word)
+			length += len([]rune(word))
+		}
+		lines[i] = wrapped.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Template returns the template for dest, or "" if its elements are
+// simply joined together
+func (c *Config) Template(dest Destination) string {
+	if dest == DestTitle {
+		return c.TitleTemplate
+	}
+	return c.BodyTemplate
+}
+
+// parseTemplate parses a title-template or body-template.
+// Referring to an element that doesn't exist is an error when it's run.
+func parseTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(TemplateFuncs).Option("missingkey=error").Parse(text)
+}
+
+// TemplateData returns what templates are given: every element's value
+// keyed by its name, and by its name with - replaced by _ so it can be
+// written {{.change_type}}. Values that can hold several selections
+// (see IsMultiple) are lists of them. Everything else is a string, ""
+// when it was skipped.
+func (c *Config) TemplateData(values map[string]string) map[string]any {
+	data := map[string]any{}
+	for _, elem := range c.Elements {
+		if GetEffectiveType(elem) == TypeConfirmation {
+			continue
+		}
+		var value any = values[elem.Name]
+		if elem.IsMultiple() {
+			selections := elem.Selections(values[elem.Name])
+			if selections == nil {
+				selections = []string{}
+			}
+			value = selections
+		}
+		data[elem.Name] = value
+		data[strings.ReplaceAll(elem.Name, "-", "_")] = value
+	}
+	return data
+}
+
+// RenderTemplate renders dest's template with the element values,
+// keyed by element name
+func (c *Config) RenderTemplate(dest Destination, values map[string]string) (string, error) {
+	key := TitleTemplateKey
+	if dest == DestBody {
+		key = BodyTemplateKey
+	}
+	tmpl, err := parseTemplate(key, c.Template(dest))
+	if err != nil {
+		return "", err
+	}
+	var s strings.Builder
+	if err := tmpl.Execute(&s, c.TemplateData(values)); err != nil {
+		return "", err
+	}
+	return s.String(), nil
+}
+
+// parseTemplates reads the template keys from the top of the document
+func parseTemplates(document *yaml.Node, cfg *Config) error {
+	if document == nil {
+		return nil
+	}
+	root := document.Content[0]
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		keyNode, valueNode := root.Content[i], root.Content[i+1]
+		if !isTemplateKey(keyNode.Value) {
+			continue
+		}
+		if valueNode.Kind != yaml.ScalarNode {
+			return fmt.Errorf("line %d: %s must be a string", keyNode.Line, keyNode.Value)
+		}
+		if keyNode.Value == TitleTemplateKey {
+			cfg.TitleTemplate = valueNode.Value
+		} else {
+			cfg.BodyTemplate = valueNode.Value
+		}
+		if cfg.templatePos == nil {
+			cfg.templatePos = map[string]Position{}
+		}
+		cfg.templatePos[keyNode.Value] = Position{Line: keyNode.Line, Column: keyNode.Column}
+	}
+	return nil
+}
+
+// templateProblems parses each template and renders it with sample
+// values, so mistakes are found before anyone answers the prompts
+func templateProblems(cfg *Config) []ValidationError {
+	sample := map[string]string{}
+	for _, elem := range cfg.Elements {
+		sample[elem.Name] = "sample"
+	}
+
+	var problems []ValidationError
+	for _, dest := range []Destination{DestTitle, DestBody} {
+		if cfg.Template(dest) == "" {
+			continue
+		}
+		key := TitleTemplateKey
+		if dest == DestBody {
+			key = BodyTemplateKey
+		}
+		rendered, err := cfg.RenderTemplate(dest, sample)
+		message := ""
+		switch {
+		case err != nil:
+			message = err.Error()
+		case dest == DestTitle && strings.Contains(strings.TrimSpace(rendered), "\n"):
+			message = "title-template cannot contain newlines"
+		default:
+			continue
+		}
+		problems = append(problems, ValidationError{
+			File:    cfg.FilePath,
+			Pos:     cfg.templatePos[key],
+			Message: message,
+		})
+	}
+	return problems
+}
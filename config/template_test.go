@@ -0,0 +1,135 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const templatedConfig = `title-template: '{{.change_type}}{{if .scope}}({{.scope}}){{end}}: {{.subject}}'
+body-template: |
+  {{wrap 20 .description}}
+  {{if .tags}}
+  Tags: {{join ", " .tags | upper}}{{end}}
+  Scope: {{default "everything" .scope | lower}}
+change-type:
+  destination: title
+  type: select
+  options: [fix, feat]
+scope:
+  destination: title
+  type: text
+  allow-empty: true
+subject:
+  destination: title
+  type: text
+description:
+  destination: body
+  type: multiline-text
+tags:
+  destination: body
+  type: multi-select
+  record-as: list
+  options: [ui, core]
+  allow-empty: true
+`
+
+func loadTemplatedConfig(t *testing.T, source string) *Config {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), ".git-com.yaml")
+	if err := os.WriteFile(path, []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadConfigFromPath(path)
+	if err != nil {
+		t.Fatalf("LoadConfigFromPath() error = %v", err)
+	}
+	return cfg
+}
+
+func TestLoadConfigFromPath_Templates(t *testing.T) {
+	cfg := loadTemplatedConfig(t, templatedConfig)
+	if len(cfg.Elements) != 5 || cfg.Elements[0].Name != "change-type" {
+		t.Errorf("elements = %v", cfg.Elements)
+	}
+	if !strings.HasPrefix(cfg.TitleTemplate, "{{.change_type}}") || !strings.Contains(cfg.BodyTemplate, "wrap 20") {
+		t.Errorf("templates = %q, %q", cfg.TitleTemplate, cfg.BodyTemplate)
+	}
+	if problems := CheckConfig(cfg); len(problems) != 0 {
+		t.Errorf("CheckConfig() = %v", problems)
+	}
+}
+
+func TestRenderTemplate(t *testing.T) {
+	cfg := loadTemplatedConfig(t, templatedConfig)
+	values := map[string]string{
+		"change-type": "fix",
+		"subject":     "handle empty input",
+		"description": "It used to crash whenever the input was empty.",
+		"tags":        "\n- ui\n- core\n",
+	}
+
+	title, err := cfg.RenderTemplate(DestTitle, values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if title != "fix: handle empty input" {
+		t.Errorf("title = %q", title)
+	}
+
+	values["scope"] = "Parser"
+	if title, _ = cfg.RenderTemplate(DestTitle, values); title != "fix(Parser): handle empty input" {
+		t.Errorf("title with scope = %q", title)
+	}
+
+	body, err := cfg.RenderTemplate(DestBody, values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "It used to crash\nwhenever the input\nwas empty.\n\nTags: UI, CORE\nScope: parser\n"
+	if body != want {
+		t.Errorf("body = %q, want %q", body, want)
+	}
+}
+
+func TestCheckConfig_TemplateProblems(t *testing.T) {
+	elements := "subject:\n  destination: title\n  type: text\n"
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{"syntax", "title-template: '{{.subject'", "unclosed action"},
+		{"unknown element", "title-template: '{{.subjct}}'", "subjct"},
+		{"newline in title", "title-template: \"{{.subject}}\\n\\nmore\"", "cannot contain newlines"},
+		{"bad helper arguments", "body-template: '{{wrap \"x\" .subject}}'", "expected integer"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := loadTemplatedConfig(t, tt.template+"\n"+elements)
+			problems := CheckConfig(cfg)
+			if len(problems) != 1 || !strings.Contains(problems[0].Message, tt.want) {
+				t.Fatalf("CheckConfig() = %v, want a problem mentioning %q", problems, tt.want)
+			}
+			if problems[0].Pos.Line != 1 {
+				t.Errorf("problem is on line %d, want 1", problems[0].Pos.Line)
+			}
+		})
+	}
+}
+
+func TestSaveConfig_Templates(t *testing.T) {
+	cfg := loadTemplatedConfig(t, templatedConfig)
+	if err := SaveConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	saved, err := LoadConfigFromPath(cfg.FilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.TitleTemplate != cfg.TitleTemplate || saved.BodyTemplate != cfg.BodyTemplate || len(saved.Elements) != 5 {
+		t.Errorf("saved = %q, %q, %d elements", saved.TitleTemplate, saved.BodyTemplate, len(saved.Elements))
+	}
+}
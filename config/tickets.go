@@ -0,0 +1,155 @@
+package config
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Ticket is one entry in a ticket element's list
+type Ticket struct {
+	ID    string
+	Title string
+}
+
+// Label is how the ticket is shown in the picker
+func (t Ticket) Label() string {
+	if t.Title == "" {
+		return t.ID
+	}
+	return t.ID + ": " + t.Title
+}
+
+// TicketValue is what's recorded in the message for t
+func (e *Element) TicketValue(t Ticket) string {
+	if e.IsRecordTitle() {
+		return t.Label()
+	}
+	return t.ID
+}
+
+// LoadTickets reads a ticket element's list from its ticket-file, which
+// is relative to the config file, or the output of its ticket-command
+func (c *Config) LoadTickets(elem Element) ([]Ticket, error) {
+	var data []byte
+	var err error
+	if elem.TicketCommand != "" {
+		cmd := exec.Command("sh", "-c", elem.TicketCommand)
+		cmd.Stderr = os.Stderr
+		if data, err = cmd.Output(); err != nil {
+			return nil, fmt.Errorf("%s: ticket-command failed: %w", elem.Name, err)
+		}
+	} else {
+		path := elem.TicketFile
+		if !filepath.IsAbs(path) && c.FilePath != "" {
+			path = filepath.Join(filepath.Dir(c.FilePath), path)
+		}
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("%s: %w", elem.Name, err)
+		}
+	}
+
+	tickets, err := ParseTickets(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", elem.Name, err)
+	}
+	return tickets, nil
+}
+
+// ParseTickets reads a list of tickets. JSON is an array of objects with
+// an id (or number, or key) and a title (or summary). Anything else is
+// CSV with the id in the first column and the title in the second; a
+// first row starting with "id" is a header and skipped.
+func ParseTickets(data []byte) ([]Ticket, error) {
+	data = bytes.TrimSpace(data)
+	if bytes.HasPrefix(data, []byte("[")) {
+		return parseJSONTickets(data)
+	}
+	return parseCSVTickets(data)
+}
+
+func parseJSONTickets(data []byte) ([]Ticket, error) {
+	var entries []map[string]any
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("couldn't read tickets: %w", err)
+	}
+
+	var tickets []Ticket
+	for i, entry := range entries {
+		id := firstField(entry, "id", "number", "key")
+		if id == "" {
+			return nil, fmt.Errorf("ticket %d has no id", i+1)
+		}
+		tickets = append(tickets, Ticket{ID: id, Title: firstField(entry, "title", "summary")})
+	}
+	return tickets, nil
+}
+
+// firstField returns the first of names that entry has, as a string
+func firstField(entry map[string]any, names ...string) string {
+	for _, name := range names {
+		switch value := entry[name].(type) {
+		case nil:
+			continue
+		case float64:
+			// JSON numbers, like GitHub issue numbers
+			return fmt.Sprintf("%.0f", value)
+		default:
+			return strings.TrimSpace(fmt.Sprint(value))
+		}
+	}
+	return ""
+}
+
+func parseCSVTickets(data []byte) ([]Ticket, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read tickets: %w", err)
+	}
+
+	var tickets []Ticket
+	for i, row := range rows {
+		if i == 0 && isIDHeader(row[0]) {
+			continue
+		}
+		ticket := Ticket{ID: strings.TrimSpace(row[0])}
+		if len(row) > 1 {
+			ticket.Title = strings.TrimSpace(row[1])
+		}
+		if ticket.ID != "" {
+			tickets = append(tickets, ticket)
+		}
+	}
+	return tickets, nil
+}
+
+// isIDHeader returns true if cell names the id column, the same way the
+// JSON fields are named
+func isIDHeader(cell string) bool {
+	cell = strings.ToLower(strings.TrimSpace(cell))
+	return cell == "id" || cell == "number" || cell == "key"
+}
+
+// compilePattern compiles a ticket element's pattern, which has to
+// match the whole of what's typed in
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + pattern + ")$")
+}
+
+// MatchesPattern returns true if a ticket that isn't in the list may be
+// entered. Without a pattern, only listed tickets can be chosen.
+func (e *Element) MatchesPattern(id string) bool {
+	if e.Pattern == "" {
+		return false
+	}
+	re, err := compilePattern(e.Pattern)
+	return err == nil && re.MatchString(id)
+}
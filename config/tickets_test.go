@@ -0,0 +1,135 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseTickets(t *testing.T) {
+	want := []Ticket{{ID: "ABC-1", Title: "Crash on empty input"}, {ID: "ABC-2", Title: "Add dark mode"}}
+	tests := []struct {
+		name string
+		data string
+		want []Ticket
+	}{
+		{
+			name: "JSON",
+			data: `[{"id": "ABC-1", "title": "Crash on empty input"}, {"id": "ABC-2", "title": "Add dark mode"}]`,
+			want: want,
+		},
+		{
+			name: "JSON with numbers",
+			data: `[{"number": 12, "title": "Crash on empty input"}]`,
+			want: []Ticket{{ID: "12", Title: "Crash on empty input"}},
+		},
+		{
+			name: "JSON with numeric ids",
+			data: `[{"id": 1234567, "title": "Crash on empty input"}, {"id": 8}]`,
+			want: []Ticket{{ID: "1234567", Title: "Crash on empty input"}, {ID: "8"}},
+		},
+		{
+			name: "JSON with keys and summaries",
+			data: `[{"key": "ABC-1", "summary": "Crash on empty input"}]`,
+			want: want[:1],
+		},
+		{
+			name: "CSV with a header",
+			data: "id,title\nABC-1,Crash on empty input\nABC-2,Add dark mode\n",
+			want: want,
+		},
+		{
+			name: "CSV with a capitalized header",
+			data: "ID,Title\nABC-1,Crash on empty input\nABC-2,Add dark mode\n",
+			want: want,
+		},
+		{
+			name: "CSV with a key and summary header",
+			data: "key,summary\nABC-1,Crash on empty input\nABC-2,Add dark mode\n",
+			want: want,
+		},
+		{
+			name: "CSV without a header",
+			data: "ABC-1,Crash on empty input\nABC-2,\"Add dark mode\"\n",
+			want: want,
+		},
+		{
+			name: "CSV without titles",
+			data: "ABC-1\nABC-2\n",
+			want: []Ticket{{ID: "ABC-1"}, {ID: "ABC-2"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTickets([]byte(tt.data))
+			if err != nil {
+				t.Fatalf("ParseTickets() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseTickets() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := ParseTickets([]byte(`[{"title": "no id"}]`)); err == nil {
+		t.Error("ParseTickets() without an id expected an error")
+	}
+}
+
+func TestLoadTickets(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "tickets.csv"), []byte("ABC-1,Crash on empty input\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &Config{FilePath: filepath.Join(dir, ".git-com.yaml")}
+
+	// files are relative to the config file
+	tickets, err := cfg.LoadTickets(Element{Name: "ticket", TicketFile: "tickets.csv"})
+	if err != nil || len(tickets) != 1 || tickets[0].ID != "ABC-1" {
+		t.Errorf("LoadTickets(file) = %v, %v", tickets, err)
+	}
+
+	tickets, err = cfg.LoadTickets(Element{Name: "ticket", TicketCommand: `echo '[{"id": "ABC-2", "title": "Add dark mode"}]'`})
+	if err != nil || len(tickets) != 1 || tickets[0].Label() != "ABC-2: Add dark mode" {
+		t.Errorf("LoadTickets(command) = %v, %v", tickets, err)
+	}
+
+	if _, err := cfg.LoadTickets(Element{Name: "ticket", TicketFile: "missing.csv"}); err == nil {
+		t.Error("LoadTickets(missing file) expected an error")
+	}
+}
+
+func TestTicketValue(t *testing.T) {
+	ticket := Ticket{ID: "ABC-1", Title: "Crash on empty input"}
+	if got := (&Element{}).TicketValue(ticket); got != "ABC-1" {
+		t.Errorf("TicketValue() = %q", got)
+	}
+	if got := (&Element{RecordTitle: boolPtr(true)}).TicketValue(ticket); got != "ABC-1: Crash on empty input" {
+		t.Errorf("TicketValue() with record-title = %q", got)
+	}
+}
+
+func TestMatchesPattern(t *testing.T) {
+	elem := Element{Pattern: `[A-Z]+-\d+`}
+	for id, want := range map[string]bool{"ABC-12": true, "abc-12": false, "ABC-12 and more": false} {
+		if got := elem.MatchesPattern(id); got != want {
+			t.Errorf("MatchesPattern(%q) = %v, want %v", id, got, want)
+		}
+	}
+	if (&Element{}).MatchesPattern("ABC-12") {
+		t.Error("MatchesPattern() without a pattern should reject everything")
+	}
+}
+
+func TestSelections_MultipleTickets(t *testing.T) {
+	elem := Element{Type: TypeTicket, Multiple: boolPtr(true)}
+	if got := elem.Selections("ABC-1, ABC-2"); !reflect.DeepEqual(got, []string{"ABC-1", "ABC-2"}) {
+		t.Errorf("Selections() = %v", got)
+	}
+	single := Element{Type: TypeTicket}
+	if got := single.Selections("ABC-1, ABC-2"); len(got) != 1 {
+		t.Errorf("Selections() of a single ticket = %v", got)
+	}
+}
@@ -1,5 +1,13 @@
 package config
 
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
 // ElementType represents the type of input element
 type ElementType string
 
@@ -9,6 +17,9 @@ const (
 	TypeSelect        ElementType = "select"
 	TypeMultiSelect   ElementType = "multi-select"
 	TypeConfirmation  ElementType = "confirmation"
+	TypeCoAuthors     ElementType = "co-authors"
+	TypeComputed      ElementType = "computed"
+	TypeTicket        ElementType = "ticket"
 )
 
 // Destination represents where the element value goes
@@ -36,6 +47,40 @@ const (
 	RecordAsJoinedString RecordAs = "joined-string"
 )
 
+// OrderBy is the order a multi-select's selections are recorded in
+type OrderBy string
+
+const (
+	OrderBySelection OrderBy = "selection" // the order they were chosen in
+	OrderByOptions   OrderBy = "options"   // the order of the options
+	OrderByAlpha     OrderBy = "alpha"     // alphabetically
+)
+
+// Version bumps that options can call for in version-bump
+const (
+	BumpMajor = "major"
+	BumpMinor = "minor"
+	BumpPatch = "patch"
+)
+
+// HistorySource is where a text element's suggestions come from
+type HistorySource string
+
+const (
+	HistoryMine HistorySource = "mine" // commits by user.email
+	HistoryAll  HistorySource = "all"  // everyone's commits
+	HistoryOff  HistorySource = "off"  // no suggestions
+)
+
+// ModifiableTarget is where options added with "Other…" are saved
+type ModifiableTarget string
+
+const (
+	TargetRepoFile ModifiableTarget = "repo-file" // the committed config file
+	TargetLocal    ModifiableTarget = "local"     // a file under .git/ that's merged in at load time
+	TargetStage    ModifiableTarget = "stage"     // the committed config file, then `git add` it
+)
+
 // Default text that will be used when not provided by the user
 const (
 	// JoinSeparator is the string used to join array elements.
@@ -59,12 +104,18 @@ type Element struct {
 	AllowEmpty   *bool  `yaml:"allow-empty,omitempty"` // Pointer to distinguish unset from false
 
 	// Text-specific attributes
-	Placeholder string   `yaml:"placeholder,omitempty"`
-	DataType    DataType `yaml:"data-type,omitempty"`
+	Placeholder     string        `yaml:"placeholder,omitempty"`
+	DataType        DataType      `yaml:"data-type,omitempty"`
+	ValidateCommand string        `yaml:"validate-command,omitempty"` // run with the value on stdin, non-zero exit rejects it
+	ValidateTimeout int           `yaml:"validate-timeout,omitempty"` // seconds
+	History         HistorySource `yaml:"history,omitempty"`          // where suggestions come from
 
 	// Select/Multi-select attributes
-	Options    []string `yaml:"options,omitempty"`
-	Modifiable *bool    `yaml:"modifiable,omitempty"`
+	Options          []string          `yaml:"options,omitempty"`
+	Modifiable       *bool             `yaml:"modifiable,omitempty"`
+	ModifiableTarget ModifiableTarget  `yaml:"modifiable-target,omitempty"`
+	BreakingOptions  []string          `yaml:"breaking-options,omitempty"` // options that mark a breaking change
+	VersionBump      map[string]string `yaml:"version-bump,omitempty"`     // option → major, minor, or patch
 
 	// Multi-select specific attributes
 	RecordAs           RecordAs `yaml:"record-as,omitempty"`
@@ -72,12 +123,76 @@ type Element struct {
 	JoinString         string   `yaml:"join-string,omitempty"`
 	Limit              int      `yaml:"limit,omitempty"`
 	EmptySelectionText string   `yaml:"empty-selection-text,omitempty"`
+	OrderBy            OrderBy  `yaml:"order-by,omitempty"`
+
+	// How many recent commits co-authors and text suggestions are found in
+	HistoryDepth int `yaml:"history-depth,omitempty"`
+
+	// Computed specific attributes (one or the other)
+	Template string `yaml:"template,omitempty"`
+	Command  string `yaml:"command,omitempty"`
+
+	// Ticket specific attributes (ticket-file or ticket-command)
+	TicketFile    string `yaml:"ticket-file,omitempty"`    // JSON or CSV of ids and titles
+	TicketCommand string `yaml:"ticket-command,omitempty"` // prints JSON or CSV of ids and titles
+	RecordTitle   *bool  `yaml:"record-title,omitempty"`   // record "ID: Title" rather than just the ID
+	Pattern       string `yaml:"pattern,omitempty"`        // tickets typed in that aren't listed must match this
+	Multiple      *bool  `yaml:"multiple,omitempty"`       // allow choosing more than one ticket
+
+	// Plugin specific attributes, passed to the plugin as they are
+	PluginOptions map[string]any `yaml:"plugin-options,omitempty"`
+
+	// Source locations (populated during parsing)
+	Pos         Position            `yaml:"-"` // where the element's key is
+	AttrPos     map[string]Position `yaml:"-"` // where each attribute's key is
+	UnknownKeys []string            `yaml:"-"` // attributes we don't recognize
+}
+
+// Position is a line and column in the config file (both 1-based)
+type Position struct {
+	Line   int
+	Column int
+}
+
+// IsKnown returns true if the position came from a parsed file
+func (p Position) IsKnown() bool {
+	return p.Line > 0
+}
+
+// AttributePosition returns where attr was defined, falling back to
+// the element itself when the attribute is missing. An entry of a list
+// or map attribute (see entryAttr) falls back to the attribute.
+func (e *Element) AttributePosition(attr string) Position {
+	if pos, ok := e.AttrPos[attr]; ok {
+		return pos
+	}
+	if i := strings.Index(attr, "["); i > 0 {
+		return e.AttributePosition(attr[:i])
+	}
+	return e.Pos
+}
+
+// entryAttr names an entry of a list or map attribute, so problems with
+// it can point at the entry itself
+func entryAttr(attr, entry string) string {
+	return attr + "[" + entry + "]"
 }
 
 // Config holds the ordered list of elements parsed from YAML
 type Config struct {
 	Elements []Element
 	FilePath string // Path to the config file for saving modifications
+
+	// Optional templates the message is rendered with, instead of
+	// joining the elements together (see template.go)
+	TitleTemplate string
+	BodyTemplate  string
+	templatePos   map[string]Position
+
+	// The file as it was loaded, so modifications can preserve
+	// comments and formatting. nil if the config wasn't loaded from a file.
+	source   []byte
+	document *yaml.Node
 }
 
 // IsAllowEmpty returns true if empty input is allowed
@@ -90,6 +205,114 @@ func (e *Element) IsModifiable() bool {
 	return e.Modifiable != nil && *e.Modifiable
 }
 
+// IsMultiple returns true if the element's value can hold several
+// selections: multi-selects, and tickets with multiple: true
+func (e *Element) IsMultiple() bool {
+	switch GetEffectiveType(*e) {
+	case TypeMultiSelect:
+		return true
+	case TypeTicket:
+		return e.Multiple != nil && *e.Multiple
+	}
+	return false
+}
+
+// GetOrderBy returns the order selections are recorded in, with default
+func (e *Element) GetOrderBy() OrderBy {
+	if e.OrderBy == "" {
+		return OrderByOptions
+	}
+	return e.OrderBy
+}
+
+// IsRecordTitle returns true if tickets are recorded with their titles
+func (e *Element) IsRecordTitle() bool {
+	return e.RecordTitle != nil && *e.RecordTitle
+}
+
+// GetModifiableTarget returns where new options are saved, with default
+func (e *Element) GetModifiableTarget() ModifiableTarget {
+	if e.ModifiableTarget == "" {
+		return TargetRepoFile
+	}
+	return e.ModifiableTarget
+}
+
+// DefaultValidateTimeout is how many seconds a validate-command may run for
+const DefaultValidateTimeout = 5
+
+// GetValidateTimeout returns the validate-command timeout, with default
+func (e *Element) GetValidateTimeout() time.Duration {
+	if e.ValidateTimeout == 0 {
+		return DefaultValidateTimeout * time.Second
+	}
+	return time.Duration(e.ValidateTimeout) * time.Second
+}
+
+// GetHistory returns where suggestions come from, with default
+func (e *Element) GetHistory() HistorySource {
+	if e.History == "" {
+		return HistoryMine
+	}
+	return e.History
+}
+
+// DefaultHistoryDepth is how many commits co-authors and suggestions are found in
+const DefaultHistoryDepth = 500
+
+// GetHistoryDepth returns the number of commits to look through, with default
+func (e *Element) GetHistoryDepth() int {
+	if e.HistoryDepth == 0 {
+		return DefaultHistoryDepth
+	}
+	return e.HistoryDepth
+}
+
+// Selections splits a recorded value back into the options it was made
+// from. Only multiple values (see IsMultiple) can hold more than one.
+func (e *Element) Selections(value string) []string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	if !e.IsMultiple() {
+		return []string{value}
+	}
+
+	var selections []string
+	if e.RecordAs == RecordAsList {
+		bullet := strings.TrimSpace(e.GetBulletString())
+		for _, line := range strings.Split(value, "\n") {
+			line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), bullet))
+			if line != "" {
+				selections = append(selections, line)
+			}
+		}
+		return selections
+	}
+
+	joiner := strings.TrimSpace(e.GetJoinString())
+	if joiner == "" {
+		joiner = e.GetJoinString()
+	}
+	for _, part := range strings.Split(value, joiner) {
+		if part = strings.TrimSpace(part); part != "" {
+			selections = append(selections, part)
+		}
+	}
+	return selections
+}
+
+// IsBreaking returns true if value includes one of the breaking-options
+func (e *Element) IsBreaking(value string) bool {
+	for _, selection := range e.Selections(value) {
+		if slices.Contains(e.BreakingOptions, selection) {
+			return true
+		}
+	}
+	return false
+}
+
 // GetBulletString returns the bullet string with default
 func (e *Element) GetBulletString() string {
 	if e.BulletString == "" {
@@ -1,59 +1,251 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"reflect"
+	"slices"
+	"sort"
 	"strings"
 
 	"git-com/output"
 )
 
+// Valid values for the enumerated attributes
+var (
+	validTypes        = []string{string(TypeText), string(TypeMultilineText), string(TypeSelect), string(TypeMultiSelect), string(TypeConfirmation), string(TypeCoAuthors), string(TypeComputed), string(TypeTicket)}
+	validDestinations = []string{string(DestTitle), string(DestBody)}
+	validDataTypes    = []string{string(DataTypeString), string(DataTypeInteger), string(DataTypeFloat)}
+	validRecordAs     = []string{string(RecordAsList), string(RecordAsJoinedString)}
+	validTargets      = []string{string(TargetRepoFile), string(TargetLocal), string(TargetStage)}
+	validVersionBumps = []string{BumpMajor, BumpMinor, BumpPatch}
+	validHistory      = []string{string(HistoryMine), string(HistoryAll), string(HistoryOff)}
+	validOrderBy      = []string{string(OrderBySelection), string(OrderByOptions), string(OrderByAlpha)}
+)
+
+// textOnlyAttributes only mean something to text elements
+var textOnlyAttributes = []string{"history"}
+
+// typeRule describes which attributes an element type requires.
+// Both validation and the JSON Schema are built from this table,
+// so they can't disagree about it.
+type typeRule struct {
+	destination  bool          // true if required, false if forbidden
+	destinations []Destination // the allowed destinations, if not all of them
+	required     []string      // attributes other than destination
+}
+
+var typeRules = map[ElementType]typeRule{
+	TypeText:          {destination: true},
+	TypeMultilineText: {destination: true},
+	TypeSelect:        {destination: true, required: []string{"options"}},
+	TypeMultiSelect:   {destination: true, required: []string{"options", "record-as"}},
+	TypeConfirmation:  {destination: false},
+	// trailers have to be at the end of the body
+	TypeCoAuthors: {destination: true, destinations: []Destination{DestBody}},
+	TypeComputed:  {destination: true},
+	TypeTicket:    {destination: true},
+}
+
 // ValidateConfig validates all elements in the configuration
 // Returns true if all elements are valid, false otherwise
 // Prints error messages to stderr for invalid elements
 func ValidateConfig(cfg *Config) bool {
-	valid := true
+	problems := CheckConfig(cfg)
+	for _, problem := range problems {
+		output.PrintError(problem.Error())
+	}
+	return len(problems) == 0
+}
+
+// CheckConfig returns every problem with the configuration,
+// each one pointing at the line and column it came from
+func CheckConfig(cfg *Config) []ValidationError {
+	var problems []ValidationError
 	hasTitleElement := false
 
 	for _, elem := range cfg.Elements {
 		if elem.Destination == DestTitle {
 			hasTitleElement = true
 		}
-		if err := validateElement(elem); err != nil {
-			output.PrintError(fmt.Sprintf("\"%s\" was not configured correctly in .git-com.y[a]ml", elem.Name))
-			valid = false
-		}
+		problems = append(problems, elementProblems(cfg.FilePath, elem, validateElement(elem))...)
 	}
 
+	problems = append(problems, trailerProblems(cfg)...)
+	problems = append(problems, templateProblems(cfg)...)
+
+	// report problems in the order they appear in the file
+	sort.SliceStable(problems, func(i, j int) bool {
+		return problems[i].Pos.Line < problems[j].Pos.Line
+	})
+
 	if !hasTitleElement {
-		output.PrintError("At least one element in .git-com.y[a]ml must have destination: title")
-		valid = false
+		problems = append(problems, ValidationError{
+			File:    cfg.FilePath,
+			Message: "at least one element must have destination: title",
+		})
 	}
 
-	return valid
+	return problems
+}
+
+// trailerProblems reports elements that write to the body after a
+// co-authors element. git only reads trailers from the last paragraph,
+// so anything after them would hide them. A body-template decides the
+// order itself.
+func trailerProblems(cfg *Config) []ValidationError {
+	if cfg.Template(DestBody) != "" {
+		return nil
+	}
+	var problems []ValidationError
+	coAuthors := ""
+	for _, elem := range cfg.Elements {
+		elemType := inferElementType(elem)
+		if elemType == TypeCoAuthors {
+			coAuthors = elem.Name
+			continue
+		}
+		if coAuthors == "" || elemType == TypeConfirmation || elem.Destination != DestBody {
+			continue
+		}
+		err := attrErrorf("destination", "%s comes after %s, but co-authors have to be at the end of the body", elem.Name, coAuthors)
+		problems = append(problems, elementProblems(cfg.FilePath, elem, err)...)
+	}
+	return problems
 }
 
 // validateElement validates a single element based on its type
+// Every problem found is returned, combined with errors.Join
 func validateElement(elem Element) error {
-	elemType := inferElementType(elem)
+	errs := validateUnknownKeys(elem)
+	errs = append(errs, validateModifiableTarget(elem))
+	errs = append(errs, validateNonNegative(elem)...)
 
+	elemType := inferElementType(elem)
 	if elemType == "" {
-		return fmt.Errorf("missing type")
+		errs = append(errs, attrErrorf("type", "missing type"))
+		return errors.Join(errs...)
 	}
 
+	errs = append(errs,
+		validateTextOnly(elemType, elem),
+		validateValidateCommand(elemType, elem),
+		validatePluginOptions(elemType, elem),
+		validateBreakingOptions(elemType, elem),
+		validateVersionBump(elemType, elem),
+	)
+
 	// Confirmation elements have different validation rules
 	if elemType == TypeConfirmation {
-		return validateConfirmationElement(elem)
+		errs = append(errs, validateConfirmationElement(elem))
+		return errors.Join(errs...)
 	}
 
-	if err := validateDestination(elem); err != nil {
-		return err
+	errs = append(errs,
+		validateDestination(elemType, elem),
+		validateTitleConstraints(elem),
+		validateByType(elemType, elem),
+	)
+	return errors.Join(errs...)
+}
+
+// validateUnknownKeys reports attributes we don't recognize.
+// yaml.v3 would otherwise silently ignore them.
+func validateUnknownKeys(elem Element) []error {
+	var errs []error
+	names := attributeNames()
+	for _, key := range elem.UnknownKeys {
+		errs = append(errs, attrErrorf(key, "unknown attribute %q%s", key, didYouMean(key, names)))
 	}
+	return errs
+}
 
-	if err := validateTitleConstraints(elem); err != nil {
-		return err
+// validateNonNegative rejects negative numbers, which no attribute
+// means anything by (see jsonType)
+func validateNonNegative(elem Element) []error {
+	var errs []error
+	for _, attr := range attributeNames() {
+		value, _ := attributeValue(elem, attr)
+		if value.Kind() == reflect.Pointer {
+			if value.IsNil() {
+				continue
+			}
+			value = value.Elem()
+		}
+		if value.Kind() == reflect.Int && value.Int() < 0 {
+			errs = append(errs, attrErrorf(attr, "%s can't be negative: %d", attr, value.Int()))
+		}
 	}
+	return errs
+}
 
-	return validateByType(elemType, elem)
+// validateModifiableTarget checks where "Other…" additions are saved
+func validateModifiableTarget(elem Element) error {
+	if !isAttributeSet(elem, "modifiable-target") {
+		return nil
+	}
+	for _, target := range validTargets {
+		if string(elem.ModifiableTarget) == target {
+			return nil
+		}
+	}
+	return attrErrorf("modifiable-target", "invalid modifiable-target: %s%s",
+		elem.ModifiableTarget, didYouMean(string(elem.ModifiableTarget), validTargets))
+}
+
+// validateVersionBump checks that every option of a select or
+// multi-select maps to a known bump
+func validateVersionBump(elemType ElementType, elem Element) error {
+	if !isAttributeSet(elem, "version-bump") {
+		return nil
+	}
+	if elemType != TypeSelect && elemType != TypeMultiSelect {
+		return attrErrorf("version-bump", "version-bump can only be used on select and multi-select elements")
+	}
+	var errs []error
+	for _, option := range sortedKeys(elem.VersionBump) {
+		bump := elem.VersionBump[option]
+		attr := entryAttr("version-bump", option)
+		if !slices.Contains(elem.Options, option) {
+			errs = append(errs, attrErrorf(attr, "version-bump has %s, which isn't one of the options%s",
+				option, didYouMean(option, elem.Options)))
+		}
+		if !slices.Contains(validVersionBumps, bump) {
+			errs = append(errs, attrErrorf(attr, "invalid version-bump for %s: %s (must be major, minor, or patch)%s",
+				option, bump, didYouMean(bump, validVersionBumps)))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validateBreakingOptions checks that breaking-options are options of
+// a select or multi-select, so a typo doesn't hide breaking changes
+// from changelog and next-version
+func validateBreakingOptions(elemType ElementType, elem Element) error {
+	if !isAttributeSet(elem, "breaking-options") {
+		return nil
+	}
+	if elemType != TypeSelect && elemType != TypeMultiSelect {
+		return attrErrorf("breaking-options", "breaking-options can only be used on select and multi-select elements")
+	}
+	var errs []error
+	for _, option := range elem.BreakingOptions {
+		if !slices.Contains(elem.Options, option) {
+			errs = append(errs, attrErrorf(entryAttr("breaking-options", option), "breaking-options has %s, which isn't one of the options%s",
+				option, didYouMean(option, elem.Options)))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// sortedKeys returns the keys of m in order, so problems are reported consistently
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
 }
 
 // inferElementType returns the element type, inferring from data-type if needed
@@ -64,18 +256,81 @@ func inferElementType(elem Element) ElementType {
 	return elem.Type
 }
 
+// validateTextOnly rejects textOnlyAttributes on elements that aren't typed in
+func validateTextOnly(elemType ElementType, elem Element) error {
+	if elemType == TypeText {
+		return nil
+	}
+	var errs []error
+	for _, attr := range textOnlyAttributes {
+		if isAttributeSet(elem, attr) {
+			errs = append(errs, attrErrorf(attr, "%s can only be used on text elements", attr))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validateValidateCommand only allows validate-command where something
+// is typed in for it to check. Selects only have the options added with
+// "Other…", so they have to be modifiable.
+func validateValidateCommand(elemType ElementType, elem Element) error {
+	if !isAttributeSet(elem, "validate-command") {
+		return nil
+	}
+	switch elemType {
+	case TypeText, TypeMultilineText:
+		return nil
+	case TypeSelect, TypeMultiSelect:
+		if !elem.IsModifiable() {
+			return attrErrorf("validate-command", "validate-command only checks options added with \"Other…\", so a %s needs modifiable: true", elemType)
+		}
+		return nil
+	}
+	return attrErrorf("validate-command", "validate-command can't be used on %s elements, they have nothing typed in to check", elemType)
+}
+
+// validatePluginOptions rejects plugin-options on the built in types,
+// which would ignore them
+func validatePluginOptions(elemType ElementType, elem Element) error {
+	// an empty map still counts, since it was written
+	_, written := elem.AttrPos["plugin-options"]
+	if IsBuiltinType(elemType) && (written || len(elem.PluginOptions) > 0) {
+		return attrErrorf("plugin-options", "plugin-options can only be used with plugin types")
+	}
+	return nil
+}
+
+// validateRequired checks for the attributes typeRules requires of elemType
+func validateRequired(elemType ElementType, elem Element) error {
+	var errs []error
+	for _, attr := range typeRules[elemType].required {
+		if !isAttributeSet(elem, attr) {
+			errs = append(errs, attrErrorf(attr, "%s element must have %s", elemType, attr))
+		}
+	}
+	return errors.Join(errs...)
+}
+
 // validateConfirmationElement validates confirmation-specific rules
 func validateConfirmationElement(elem Element) error {
+	// older versions of SaveConfig wrote `destination: ""` so allow that
 	if elem.Destination != "" {
-		return fmt.Errorf("confirmation elements cannot have a destination")
+		return attrErrorf("destination", "confirmation elements cannot have a destination")
 	}
 	return nil
 }
 
 // validateDestination checks that the destination is valid for non-confirmation elements
-func validateDestination(elem Element) error {
+func validateDestination(elemType ElementType, elem Element) error {
+	if !isAttributeSet(elem, "destination") {
+		return attrErrorf("destination", "missing destination (must be title or body)")
+	}
 	if elem.Destination != DestTitle && elem.Destination != DestBody {
-		return fmt.Errorf("invalid destination: %s", elem.Destination)
+		return attrErrorf("destination", "invalid destination: %s%s",
+			elem.Destination, didYouMean(string(elem.Destination), validDestinations))
+	}
+	if allowed := typeRules[elemType].destinations; len(allowed) > 0 && !slices.Contains(allowed, elem.Destination) {
+		return attrErrorf("destination", "%s elements must have destination: %s", elemType, allowed[0])
 	}
 	return nil
 }
@@ -85,13 +340,14 @@ func validateTitleConstraints(elem Element) error {
 	if elem.Destination != DestTitle {
 		return nil
 	}
+	var errs []error
 	if strings.Contains(elem.BeforeString, "\n") {
-		return fmt.Errorf("before-string cannot contain newlines for title destination")
+		errs = append(errs, attrErrorf("before-string", "before-string cannot contain newlines for title destination"))
 	}
 	if strings.Contains(elem.AfterString, "\n") {
-		return fmt.Errorf("after-string cannot contain newlines for title destination")
+		errs = append(errs, attrErrorf("after-string", "after-string cannot contain newlines for title destination"))
 	}
-	return nil
+	return errors.Join(errs...)
 }
 
 // validateByType dispatches to type-specific validation
@@ -105,55 +361,128 @@ func validateByType(elemType ElementType, elem Element) error {
 		return validateSelectElement(elem)
 	case TypeMultiSelect:
 		return validateMultiSelectElement(elem)
+	case TypeCoAuthors:
+		return nil
+	case TypeComputed:
+		return validateComputedElement(elem)
+	case TypeTicket:
+		return validateTicketElement(elem)
 	default:
-		return fmt.Errorf("unknown type: %s", elemType)
+		if _, ok := FindPlugin(elemType); ok {
+			// the plugin checks its own attributes
+			return nil
+		}
+		hint := didYouMean(string(elemType), validTypes)
+		if hint == "" && !pluginTypeRegexp.MatchString(string(elemType)) {
+			hint = " (plugin types are lowercase letters, digits, and dashes)"
+		} else if hint == "" {
+			hint = fmt.Sprintf(" (and there's no %s%s on PATH)", PluginPrefix, elemType)
+		}
+		return attrErrorf("type", "unknown type: %s%s", elemType, hint)
 	}
 }
 
 // validateTextElement validates a text element
 func validateTextElement(elem Element) error {
+	var errs []error
 	// Validate data-type if present
 	if elem.DataType != "" {
 		switch elem.DataType {
 		case DataTypeString, DataTypeInteger, DataTypeFloat:
 			// Valid
 		default:
-			return fmt.Errorf("invalid data-type: %s", elem.DataType)
+			errs = append(errs, attrErrorf("data-type", "invalid data-type: %s%s",
+				elem.DataType, didYouMean(string(elem.DataType), validDataTypes)))
 		}
 	}
-	return nil
+	if isAttributeSet(elem, "history") && !slices.Contains(validHistory, string(elem.History)) {
+		errs = append(errs, attrErrorf("history", "invalid history: %s (must be mine, all, or off)%s",
+			elem.History, didYouMean(string(elem.History), validHistory)))
+	}
+	return errors.Join(errs...)
 }
 
 // validateSelectElement validates a select element
 func validateSelectElement(elem Element) error {
-	if len(elem.Options) == 0 {
-		return fmt.Errorf("select element must have options")
-	}
-	return nil
+	return validateRequired(TypeSelect, elem)
 }
 
 // validateMultiSelectElement validates a multi-select element
 func validateMultiSelectElement(elem Element) error {
-	if len(elem.Options) == 0 {
-		return fmt.Errorf("multi-select element must have options")
-	}
-	if elem.RecordAs == "" {
-		return fmt.Errorf("multi-select element must have record-as")
-	}
-	if elem.RecordAs != RecordAsList && elem.RecordAs != RecordAsJoinedString {
-		return fmt.Errorf("invalid record-as: %s", elem.RecordAs)
+	errs := []error{validateRequired(TypeMultiSelect, elem)}
+	if isAttributeSet(elem, "record-as") && elem.RecordAs != RecordAsList && elem.RecordAs != RecordAsJoinedString {
+		errs = append(errs, attrErrorf("record-as", "invalid record-as: %s%s",
+			elem.RecordAs, didYouMean(string(elem.RecordAs), validRecordAs)))
 	}
 	// Title destination requires joined-string (list would have newlines)
 	if elem.Destination == DestTitle && elem.RecordAs == RecordAsList {
-		return fmt.Errorf("multi-select with destination title must use record-as: joined-string")
+		errs = append(errs, attrErrorf("record-as", "multi-select with destination title must use record-as: joined-string"))
+	}
+	if isAttributeSet(elem, "order-by") && !slices.Contains(validOrderBy, string(elem.OrderBy)) {
+		errs = append(errs, attrErrorf("order-by", "invalid order-by: %s (must be selection, options, or alpha)%s",
+			elem.OrderBy, didYouMean(string(elem.OrderBy), validOrderBy)))
 	}
 	// Cannot define empty-selection-text if allow-empty is false or not present
 	if elem.HasEmptySelectionText() && !elem.IsAllowEmpty() {
-		return fmt.Errorf("cannot define empty-selection-text when allow-empty is false or not set")
+		errs = append(errs, attrErrorf("empty-selection-text", "cannot define empty-selection-text when allow-empty is false or not set"))
+	}
+	return errors.Join(errs...)
+}
+
+// validateComputedElement checks that a computed element has exactly one
+// source for its value, and that a template can be parsed
+func validateComputedElement(elem Element) error {
+	if err := validateOneOf(TypeComputed, elem, "template", "command"); err != nil {
+		return err
+	}
+	if isAttributeSet(elem, "template") {
+		if _, err := parseComputedTemplate(elem); err != nil {
+			return attrErrorf("template", "%s", err)
+		}
 	}
 	return nil
 }
 
+// validateTicketElement checks that a ticket element has exactly one
+// list of tickets, and that its pattern can be compiled
+func validateTicketElement(elem Element) error {
+	errs := []error{validateOneOf(TypeTicket, elem, "ticket-file", "ticket-command")}
+	if elem.Pattern != "" {
+		if _, err := compilePattern(elem.Pattern); err != nil {
+			errs = append(errs, attrErrorf("pattern", "invalid pattern: %s", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validateOneOf checks that exactly one of the attributes a and b is set
+func validateOneOf(elemType ElementType, elem Element, a, b string) error {
+	hasA, hasB := isAttributeSet(elem, a), isAttributeSet(elem, b)
+	switch {
+	case !hasA && !hasB:
+		return attrErrorf("type", "%s element must have %s or %s", elemType, a, b)
+	case hasA && hasB:
+		return attrErrorf(b, "%s element can't have both %s and %s", elemType, a, b)
+	}
+	return nil
+}
+
+// isAttributeSet returns true if attr was written in the config file,
+// or has a non-empty value. Lists must have at least one item.
+func isAttributeSet(elem Element, attr string) bool {
+	value, ok := attributeValue(elem, attr)
+	if !ok {
+		return false
+	}
+	if value.Kind() == reflect.Slice || value.Kind() == reflect.Map {
+		return value.Len() > 0
+	}
+	if _, present := elem.AttrPos[attr]; present {
+		return true
+	}
+	return !value.IsZero()
+}
+
 // GetEffectiveType returns the effective type of an element
 // (handles inference from data-type)
 func GetEffectiveType(elem Element) ElementType {
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -253,6 +254,111 @@ func TestValidateElement(t *testing.T) {
 			},
 			wantErr: false,
 		},
+
+		// validate-command
+		{
+			name:    "text with validate-command is valid",
+			elem:    Element{Destination: DestTitle, Type: TypeText, ValidateCommand: "grep -q ."},
+			wantErr: false,
+		},
+		{
+			name:    "select with validate-command",
+			elem:    Element{Destination: DestTitle, Type: TypeSelect, Options: []string{"a"}, ValidateCommand: "grep -q ."},
+			wantErr: true,
+		},
+		{
+			name:    "modifiable select with validate-command is valid",
+			elem:    Element{Destination: DestTitle, Type: TypeSelect, Options: []string{"a"}, Modifiable: boolPtr(true), ValidateCommand: "grep -q ."},
+			wantErr: false,
+		},
+		{
+			name:    "multiline-text with validate-command is valid",
+			elem:    Element{Destination: DestBody, Type: TypeMultilineText, ValidateCommand: "grep -q ."},
+			wantErr: false,
+		},
+		{
+			name:    "computed with validate-command",
+			elem:    Element{Destination: DestBody, Type: TypeComputed, Command: "echo x", ValidateCommand: "grep -q ."},
+			wantErr: true,
+		},
+
+		// breaking-options
+		{
+			name:    "breaking-options from the options is valid",
+			elem:    Element{Destination: DestTitle, Type: TypeSelect, Options: []string{"feat", "feat!"}, BreakingOptions: []string{"feat!"}},
+			wantErr: false,
+		},
+		{
+			name:    "breaking-options that aren't options",
+			elem:    Element{Destination: DestTitle, Type: TypeSelect, Options: []string{"feat", "feat!"}, BreakingOptions: []string{"feat1"}},
+			wantErr: true,
+		},
+		{
+			name:    "breaking-options on a text element",
+			elem:    Element{Destination: DestTitle, Type: TypeText, BreakingOptions: []string{"feat!"}},
+			wantErr: true,
+		},
+
+		// version-bump
+		{
+			name:    "version-bump for the options is valid",
+			elem:    Element{Destination: DestTitle, Type: TypeSelect, Options: []string{"feat", "fix"}, VersionBump: map[string]string{"feat": BumpMinor}},
+			wantErr: false,
+		},
+		{
+			name:    "version-bump for something that isn't an option",
+			elem:    Element{Destination: DestTitle, Type: TypeSelect, Options: []string{"feat", "fix"}, VersionBump: map[string]string{"feet": BumpMinor}},
+			wantErr: true,
+		},
+		{
+			name:    "version-bump on a text element",
+			elem:    Element{Destination: DestTitle, Type: TypeText, VersionBump: map[string]string{"feat": BumpMinor}},
+			wantErr: true,
+		},
+
+		// Ticket validation
+		{
+			name:    "ticket with a file is valid",
+			elem:    Element{Destination: DestBody, Type: TypeTicket, TicketFile: "tickets.csv", Pattern: `[A-Z]+-\d+`},
+			wantErr: false,
+		},
+		{
+			name:    "ticket without a list",
+			elem:    Element{Destination: DestBody, Type: TypeTicket},
+			wantErr: true,
+		},
+		{
+			name:    "ticket with a pattern that doesn't compile",
+			elem:    Element{Destination: DestBody, Type: TypeTicket, TicketFile: "tickets.csv", Pattern: "[A-Z"},
+			wantErr: true,
+		},
+
+		// Computed validation
+		{
+			name:    "computed with a template is valid",
+			elem:    Element{Destination: DestBody, Type: TypeComputed, Template: "Branch: {{branch}}"},
+			wantErr: false,
+		},
+		{
+			name:    "computed with a command is valid",
+			elem:    Element{Destination: DestBody, Type: TypeComputed, Command: "git rev-parse HEAD"},
+			wantErr: false,
+		},
+		{
+			name:    "computed without a template or command",
+			elem:    Element{Destination: DestBody, Type: TypeComputed},
+			wantErr: true,
+		},
+		{
+			name:    "computed with both a template and a command",
+			elem:    Element{Destination: DestBody, Type: TypeComputed, Template: "x", Command: "echo x"},
+			wantErr: true,
+		},
+		{
+			name:    "computed with a template that doesn't parse",
+			elem:    Element{Destination: DestBody, Type: TypeComputed, Template: "{{branch"},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -482,6 +588,26 @@ func TestValidateMultiSelectElement(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "order-by selection is valid",
+			elem: Element{
+				Destination: DestBody,
+				Options:     []string{"a", "b"},
+				RecordAs:    RecordAsList,
+				OrderBy:     OrderBySelection,
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid order-by",
+			elem: Element{
+				Destination: DestBody,
+				Options:     []string{"a", "b"},
+				RecordAs:    RecordAsList,
+				OrderBy:     "picked",
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -578,3 +704,178 @@ func TestValidateConfig_RequiresTitleElement(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckConfig_ReportsPositions(t *testing.T) {
+	yaml := `title:
+  destination: title
+  type: text
+tags:
+  destination: body
+  type: multi-select
+  optons:
+    - a
+  record-as: csv
+`
+	elements, err := parseOrderedYAML([]byte(yaml))
+	if err != nil {
+		t.Fatalf("parseOrderedYAML() error = %v", err)
+	}
+
+	problems := CheckConfig(&Config{Elements: elements, FilePath: "/tmp/.git-com.yaml"})
+
+	expected := []struct {
+		line, column int
+		contains     string
+	}{
+		{4, 1, "multi-select element must have options"},
+		{7, 3, `unknown attribute "optons" (did you mean "options"?)`},
+		{9, 3, "invalid record-as: csv"},
+	}
+
+	if len(problems) != len(expected) {
+		t.Fatalf("expected %d problems, got %d: %v", len(expected), len(problems), problems)
+	}
+	for i, want := range expected {
+		got := problems[i]
+		if got.Pos.Line != want.line || got.Pos.Column != want.column {
+			t.Errorf("problem %d: position = %d:%d, want %d:%d", i, got.Pos.Line, got.Pos.Column, want.line, want.column)
+		}
+		if got.Element != "tags" {
+			t.Errorf("problem %d: element = %q, want 'tags'", i, got.Element)
+		}
+		if !strings.Contains(got.Message, want.contains) {
+			t.Errorf("problem %d: message = %q, want it to contain %q", i, got.Message, want.contains)
+		}
+	}
+}
+
+func TestCheckConfig_NegativeNumbers(t *testing.T) {
+	yaml := `subject:
+  destination: title
+  type: text
+  validate-command: grep -q .
+  validate-timeout: -5
+pair:
+  destination: body
+  type: co-authors
+  history-depth: -1
+  limit: -1
+`
+	elements, err := parseOrderedYAML([]byte(yaml))
+	if err != nil {
+		t.Fatalf("parseOrderedYAML() error = %v", err)
+	}
+
+	problems := CheckConfig(&Config{Elements: elements})
+	expected := []struct {
+		line     int
+		contains string
+	}{
+		{5, "validate-timeout can't be negative: -5"},
+		{9, "history-depth can't be negative: -1"},
+		{10, "limit can't be negative: -1"},
+	}
+	if len(problems) != len(expected) {
+		t.Fatalf("expected %d problems, got %d: %v", len(expected), len(problems), problems)
+	}
+	for i, want := range expected {
+		if got := problems[i]; got.Pos.Line != want.line || got.Message != want.contains {
+			t.Errorf("problem %d = %d: %q, want %d: %q", i, got.Pos.Line, got.Message, want.line, want.contains)
+		}
+	}
+}
+
+func TestCheckConfig_CoAuthorsLast(t *testing.T) {
+	yaml := `subject:
+  destination: title
+  type: text
+pair:
+  destination: body
+  type: co-authors
+  before-string: "\n\n"
+ok:
+  type: confirmation
+scope:
+  destination: title
+  type: text
+notes:
+  destination: body
+  type: multiline-text
+`
+	elements, err := parseOrderedYAML([]byte(yaml))
+	if err != nil {
+		t.Fatalf("parseOrderedYAML() error = %v", err)
+	}
+
+	problems := CheckConfig(&Config{Elements: elements})
+	want := "notes comes after pair, but co-authors have to be at the end of the body"
+	if len(problems) != 1 || problems[0].Pos.Line != 14 || problems[0].Message != want {
+		t.Fatalf("problems = %v, want line 14: %q", problems, want)
+	}
+
+	// a body-template puts the trailers where it likes
+	problems = CheckConfig(&Config{Elements: elements, BodyTemplate: "{{.notes}}\n\n{{.pair}}"})
+	if len(problems) != 0 {
+		t.Errorf("with a body-template, problems = %v", problems)
+	}
+}
+
+func TestCheckConfig_PointsAtBreakingOptions(t *testing.T) {
+	yaml := `type:
+  destination: title
+  type: select
+  options: [feat, feat!]
+  breaking-options:
+    - feat!
+    - feat1
+`
+	elements, err := parseOrderedYAML([]byte(yaml))
+	if err != nil {
+		t.Fatalf("parseOrderedYAML() error = %v", err)
+	}
+
+	problems := CheckConfig(&Config{Elements: elements})
+	if len(problems) != 1 {
+		t.Fatalf("expected 1 problem, got %d: %v", len(problems), problems)
+	}
+	if got := problems[0]; got.Pos.Line != 7 || got.Pos.Column != 7 {
+		t.Errorf("position = %d:%d, want 7:7", got.Pos.Line, got.Pos.Column)
+	}
+	if want := `breaking-options has feat1, which isn't one of the options (did you mean "feat"?)`; problems[0].Message != want {
+		t.Errorf("message = %q, want %q", problems[0].Message, want)
+	}
+}
+
+func TestValidationErrorFormat(t *testing.T) {
+	err := ValidationError{
+		File:    "/nonexistent/.git-com.yaml",
+		Pos:     Position{Line: 3, Column: 5},
+		Element: "scope",
+		Message: "invalid destination: titel",
+	}
+	want := `/nonexistent/.git-com.yaml:3:5: "scope": invalid destination: titel`
+	if got := err.Error(); got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
+
+func TestDidYouMean(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{"titel", ` (did you mean "title"?)`},
+		{"multiselect", ` (did you mean "multi-select"?)`},
+		{"Body", ` (did you mean "body"?)`},
+		{"something-else-entirely", ""},
+	}
+	candidates := append(append([]string{}, validDestinations...), validTypes...)
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := didYouMean(tt.value, candidates); got != tt.expected {
+				t.Errorf("didYouMean(%q) = %q, want %q", tt.value, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// validatorKey identifies a validate-command verdict
+type validatorKey struct {
+	command string
+	value   string
+}
+
+// validatorVerdicts remembers what each validate-command said about each
+// value, so retrying or rewording doesn't run it again. Timeouts and
+// commands that couldn't be started aren't remembered.
+var (
+	validatorMu       sync.Mutex
+	validatorVerdicts = map[validatorKey]error{}
+)
+
+// CheckValue runs the element's validate-command, if it has one, with
+// value on stdin and the element's name in GIT_COM_ELEMENT. A non-zero
+// exit rejects the value; the error is whatever the command wrote to
+// stderr.
+func (e *Element) CheckValue(value string) error {
+	if e.ValidateCommand == "" {
+		return nil
+	}
+
+	key := validatorKey{command: e.ValidateCommand, value: value}
+	validatorMu.Lock()
+	verdict, ok := validatorVerdicts[key]
+	validatorMu.Unlock()
+	if ok {
+		return verdict
+	}
+
+	verdict, final := e.runValidator(value)
+	if final {
+		validatorMu.Lock()
+		validatorVerdicts[key] = verdict
+		validatorMu.Unlock()
+	}
+	return verdict
+}
+
+// runValidator runs validate-command once. final is false when the
+// command didn't get to decide, so the verdict shouldn't be remembered.
+func (e *Element) runValidator(value string) (verdict error, final bool) {
+	timeout := e.GetValidateTimeout()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "sh", "-c", e.ValidateCommand)
+	cmd.Stdin = strings.NewReader(value)
+	cmd.Stderr = &stderr
+	cmd.Env = append(os.Environ(), "GIT_COM_ELEMENT="+e.Name)
+	// don't wait forever on anything the command left running
+	cmd.WaitDelay = time.Second
+
+	err := cmd.Run()
+	if ctx.Err() != nil {
+		return fmt.Errorf("validate-command took longer than %s", timeout), false
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = fmt.Sprintf("rejected by validate-command (exit status %d)", exitErr.ExitCode())
+		}
+		return errors.New(message), true
+	}
+	if err != nil {
+		return fmt.Errorf("couldn't run validate-command: %w", err), false
+	}
+	return nil, true
+}
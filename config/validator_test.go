@@ -0,0 +1,70 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckValue(t *testing.T) {
+	elem := Element{
+		Name:            "ticket",
+		ValidateCommand: `read value; [ "$GIT_COM_ELEMENT" = ticket ] && [ "$value" = ABC-1 ] || { echo "no such ticket: $value" >&2; exit 1; }`,
+	}
+
+	if err := elem.CheckValue("ABC-1"); err != nil {
+		t.Errorf("CheckValue(ABC-1) error = %v", err)
+	}
+	err := elem.CheckValue("ABC-2")
+	if err == nil || err.Error() != "no such ticket: ABC-2" {
+		t.Errorf("CheckValue(ABC-2) error = %v, want the validator's stderr", err)
+	}
+
+	if err := (&Element{}).CheckValue("anything"); err != nil {
+		t.Errorf("CheckValue() without validate-command error = %v", err)
+	}
+}
+
+func TestCheckValue_NoStderr(t *testing.T) {
+	elem := Element{Name: "scope", ValidateCommand: "exit 3"}
+	err := elem.CheckValue("ui")
+	if err == nil || !strings.Contains(err.Error(), "exit status 3") {
+		t.Errorf("CheckValue() error = %v", err)
+	}
+}
+
+func TestCheckValue_CachesPerValue(t *testing.T) {
+	counter := filepath.Join(t.TempDir(), "runs")
+	elem := Element{Name: "scope", ValidateCommand: "echo run >> " + counter}
+
+	for _, value := range []string{"ui", "ui", "core", "ui"} {
+		if err := elem.CheckValue(value); err != nil {
+			t.Fatalf("CheckValue(%q) error = %v", value, err)
+		}
+	}
+
+	runs, err := os.ReadFile(counter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Count(string(runs), "run"); got != 2 {
+		t.Errorf("validate-command ran %d times, want once per value (2)", got)
+	}
+}
+
+func TestCheckValue_Timeout(t *testing.T) {
+	elem := Element{Name: "slow", ValidateCommand: "sleep 5", ValidateTimeout: 1}
+	err := elem.CheckValue("x")
+	if err == nil || !strings.Contains(err.Error(), "took longer than 1s") {
+		t.Errorf("CheckValue() error = %v, want a timeout", err)
+	}
+
+	// timeouts aren't remembered
+	validatorMu.Lock()
+	_, cached := validatorVerdicts[validatorKey{command: elem.ValidateCommand, value: "x"}]
+	validatorMu.Unlock()
+	if cached {
+		t.Error("a timeout was cached")
+	}
+}
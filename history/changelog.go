@@ -0,0 +1,180 @@
+// Package history reads structured information back out of commits
+// made with git-com, using the config to make sense of each message.
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/template"
+
+	"git-com/commit"
+	"git-com/config"
+	"git-com/message"
+)
+
+// OtherGroup is where entries without a value for the grouping element go
+const OtherGroup = "Other"
+
+// Entry is a single commit in a changelog
+type Entry struct {
+	Hash     string            `json:"hash"`
+	Subject  string            `json:"subject"`
+	Summary  string            `json:"summary"` // what changed, without the structured parts of the title
+	Message  string            `json:"message"`
+	Values   map[string]string `json:"values"` // element values, keyed by element name
+	Breaking bool              `json:"breaking"`
+}
+
+// ShortHash returns the abbreviated hash, as in `git log --oneline`
+func (e Entry) ShortHash() string {
+	if len(e.Hash) < 7 {
+		return e.Hash
+	}
+	return e.Hash[:7]
+}
+
+// Group is the entries that share a value for the grouping element
+type Group struct {
+	Name    string  `json:"name"`
+	Entries []Entry `json:"entries"`
+}
+
+// Changelog is the commits in a range grouped by one element's value
+type Changelog struct {
+	GroupBy  string  `json:"group_by"`
+	Groups   []Group `json:"groups"`
+	Breaking []Entry `json:"breaking"` // breaking changes, also listed in their groups
+}
+
+// NewEntry parses c's message into element values
+func NewEntry(cfg *config.Config, c commit.Summary) Entry {
+	values := message.Parse(cfg, c.Message)
+	entry := Entry{
+		Hash:    c.Hash.String(),
+		Subject: c.Subject,
+		Summary: c.Subject,
+		Message: strings.TrimSpace(c.Message),
+		Values:  values,
+	}
+
+	summaryFound := false
+	for _, elem := range cfg.Elements {
+		value, ok := values[elem.Name]
+		if !ok {
+			continue
+		}
+		if elem.IsBreaking(value) {
+			entry.Breaking = true
+		}
+		// the first free-text part of the title says what changed
+		if !summaryFound && elem.Destination == config.DestTitle && config.GetEffectiveType(elem) == config.TypeText && value != "" {
+			entry.Summary = value
+			summaryFound = true
+		}
+	}
+	return entry
+}
+
+// DefaultGroupBy returns the element changelogs are grouped by when none
+// is chosen: the first select or multi-select in the title, then the body
+func DefaultGroupBy(cfg *config.Config) string {
+	for _, dest := range []config.Destination{config.DestTitle, config.DestBody} {
+		for _, elem := range cfg.Elements {
+			elemType := config.GetEffectiveType(elem)
+			if elem.Destination == dest && (elemType == config.TypeSelect || elemType == config.TypeMultiSelect) {
+				return elem.Name
+			}
+		}
+	}
+	return ""
+}
+
+// BuildChangelog groups commits by the value of the groupBy element.
+// Groups follow the order of the element's options, then the order
+// values were first seen in. Entries with no value go in OtherGroup, last.
+// A multi-select entry is listed under each of its selections.
+func BuildChangelog(cfg *config.Config, commits []commit.Summary, groupBy string) (*Changelog, error) {
+	elem := findElement(cfg, groupBy)
+	if elem == nil {
+		return nil, fmt.Errorf("there is no %q element in %s", groupBy, cfg.FilePath)
+	}
+
+	order := slices.Clone(elem.Options)
+	grouped := map[string][]Entry{}
+	changelog := &Changelog{GroupBy: groupBy, Groups: []Group{}, Breaking: []Entry{}}
+	for _, c := range commits {
+		entry := NewEntry(cfg, c)
+		if entry.Breaking {
+			changelog.Breaking = append(changelog.Breaking, entry)
+		}
+
+		names := elem.Selections(entry.Values[groupBy])
+		if len(names) == 0 {
+			names = []string{OtherGroup}
+		}
+		for _, name := range names {
+			if name != OtherGroup && !slices.Contains(order, name) {
+				order = append(order, name)
+			}
+			grouped[name] = append(grouped[name], entry)
+		}
+	}
+
+	for _, name := range append(order, OtherGroup) {
+		if entries := grouped[name]; len(entries) > 0 {
+			changelog.Groups = append(changelog.Groups, Group{Name: name, Entries: entries})
+		}
+	}
+	return changelog, nil
+}
+
+// findElement returns the element called name, or nil
+func findElement(cfg *config.Config, name string) *config.Element {
+	for i := range cfg.Elements {
+		if cfg.Elements[i].Name == name {
+			return &cfg.Elements[i]
+		}
+	}
+	return nil
+}
+
+// WriteMarkdown writes the changelog as Markdown, with breaking changes first
+func (c *Changelog) WriteMarkdown(w io.Writer) error {
+	var sections []string
+	if len(c.Breaking) > 0 {
+		sections = append(sections, markdownSection("Breaking Changes", c.Breaking))
+	}
+	for _, group := range c.Groups {
+		sections = append(sections, markdownSection(group.Name, group.Entries))
+	}
+	_, err := io.WriteString(w, strings.Join(sections, "\n"))
+	return err
+}
+
+func markdownSection(heading string, entries []Entry) string {
+	var s strings.Builder
+	s.WriteString("## " + heading + "\n\n")
+	for _, entry := range entries {
+		s.WriteString("- " + entry.Summary + " (" + entry.ShortHash() + ")\n")
+	}
+	return s.String()
+}
+
+// WriteJSON writes the changelog as indented JSON
+func (c *Changelog) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(c)
+}
+
+// WriteTemplate renders the changelog with a Go text/template
+func (c *Changelog) WriteTemplate(w io.Writer, text string) error {
+	tmpl, err := template.New("changelog").Parse(text)
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(w, c)
+}
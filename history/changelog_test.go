@@ -0,0 +1,150 @@
+package history
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"git-com/commit"
+	"git-com/config"
+
+	"github.com/go-git/go-git/v6/plumbing"
+)
+
+const testConfig = `type:
+  destination: title
+  type: select
+  options: [feat, fix, docs, feat!, fix!]
+  breaking-options: [feat!, fix!]
+  version-bump: {feat: minor, fix: patch}
+subject:
+  destination: title
+  type: text
+  before-string: ": "
+description:
+  destination: body
+  type: multiline-text
+  allow-empty: true
+ticket:
+  destination: body
+  data-type: integer
+  before-string: "\n\nTicket: "
+  allow-empty: true
+`
+
+func loadTestConfig(t *testing.T) *config.Config {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), ".git-com.yaml")
+	if err := os.WriteFile(path, []byte(testConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.LoadConfigFromPath(path)
+	if err != nil {
+		t.Fatalf("LoadConfigFromPath() error = %v", err)
+	}
+	return cfg
+}
+
+// testCommits makes a Summary for each message, newest first
+func testCommits(messages ...string) []commit.Summary {
+	commits := make([]commit.Summary, len(messages))
+	for i, message := range messages {
+		subject, _, _ := strings.Cut(message, "\n")
+		commits[i] = commit.Summary{
+			Hash:    plumbing.NewHash(fmt.Sprintf("%07d", i+1) + strings.Repeat("0", 33)),
+			Subject: subject,
+			Message: message + "\n",
+		}
+	}
+	return commits
+}
+
+func TestBuildChangelog(t *testing.T) {
+	cfg := loadTestConfig(t)
+	commits := testCommits(
+		"fix: handle empty input",
+		"feat!: drop the old config format\n\nTicket: 12",
+		"feat: add changelogs",
+		"tidy up",
+		"perf: faster parsing",
+	)
+
+	changelog, err := BuildChangelog(cfg, commits, "type")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, group := range changelog.Groups {
+		names = append(names, fmt.Sprintf("%s:%d", group.Name, len(group.Entries)))
+	}
+	// options order, then Other for messages that don't match the config
+	want := "feat:1 fix:1 feat!:1 Other:2"
+	if got := strings.Join(names, " "); got != want {
+		t.Errorf("groups = %s, want %s", got, want)
+	}
+
+	if len(changelog.Breaking) != 1 || changelog.Breaking[0].Summary != "drop the old config format" {
+		t.Fatalf("breaking = %+v", changelog.Breaking)
+	}
+	if got := changelog.Breaking[0].Values["ticket"]; got != "12" {
+		t.Errorf("ticket = %q, want 12", got)
+	}
+}
+
+func TestBuildChangelog_UnknownElement(t *testing.T) {
+	if _, err := BuildChangelog(loadTestConfig(t), nil, "nope"); err == nil {
+		t.Error("expected an error grouping by an element that doesn't exist")
+	}
+}
+
+func TestChangelogOutput(t *testing.T) {
+	cfg := loadTestConfig(t)
+	changelog, err := BuildChangelog(cfg, testCommits("fix!: stop crashing", "docs: explain changelogs"), DefaultGroupBy(cfg))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var markdown bytes.Buffer
+	if err := changelog.WriteMarkdown(&markdown); err != nil {
+		t.Fatal(err)
+	}
+	want := "## Breaking Changes\n\n- stop crashing (0000001)\n\n" +
+		"## docs\n\n- explain changelogs (0000002)\n\n" +
+		"## fix!\n\n- stop crashing (0000001)\n"
+	if markdown.String() != want {
+		t.Errorf("markdown =\n%s\nwant\n%s", markdown.String(), want)
+	}
+
+	var decoded struct {
+		Groups []struct {
+			Name    string `json:"name"`
+			Entries []struct {
+				Breaking bool `json:"breaking"`
+			} `json:"entries"`
+		} `json:"groups"`
+	}
+	var data bytes.Buffer
+	if err := changelog.WriteJSON(&data); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.Groups) != 2 || decoded.Groups[1].Name != "fix!" || !decoded.Groups[1].Entries[0].Breaking {
+		t.Errorf("json = %s", data.String())
+	}
+
+	var rendered bytes.Buffer
+	err = changelog.WriteTemplate(&rendered, "{{range .Groups}}{{.Name}}:{{range .Entries}} {{.ShortHash}}{{end}};{{end}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := rendered.String(); got != "docs: 0000002;fix!: 0000001;" {
+		t.Errorf("template = %q", got)
+	}
+}
@@ -0,0 +1,139 @@
+package history
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"git-com/commit"
+	"git-com/config"
+	"git-com/message"
+)
+
+// Record is a single commit exported with its parsed element values
+type Record struct {
+	Hash    string    `json:"hash"`
+	Author  string    `json:"author"`
+	Email   string    `json:"email"`
+	Date    time.Time `json:"date"`
+	Subject string    `json:"subject"`
+	// Values has every element, keyed by name. Multiple values (see
+	// config.Element.IsMultiple) are []string and everything else is a
+	// string, "" when it wasn't found.
+	Values map[string]any `json:"values"`
+	// ParsedCleanly is false when the message didn't match the config,
+	// so Values may be incomplete or wrong
+	ParsedCleanly bool `json:"parsed_cleanly"`
+}
+
+// NewRecord parses c's message into element values
+func NewRecord(cfg *config.Config, c commit.Summary) Record {
+	parsed, clean := message.ParseChecked(cfg, c.Message)
+	values := map[string]any{}
+	for _, elem := range exportedElements(cfg) {
+		if elem.IsMultiple() {
+			selections := elem.Selections(parsed[elem.Name])
+			if selections == nil {
+				selections = []string{}
+			}
+			values[elem.Name] = selections
+		} else {
+			values[elem.Name] = parsed[elem.Name]
+		}
+	}
+	return Record{
+		Hash:          c.Hash.String(),
+		Author:        c.Author.Name,
+		Email:         c.Author.Email,
+		Date:          c.Author.When,
+		Subject:       c.Subject,
+		Values:        values,
+		ParsedCleanly: clean,
+	}
+}
+
+// exportedElements are the elements that add something to the message
+func exportedElements(cfg *config.Config) []config.Element {
+	var elements []config.Element
+	for _, elem := range cfg.Elements {
+		if config.GetEffectiveType(elem) != config.TypeConfirmation {
+			elements = append(elements, elem)
+		}
+	}
+	return elements
+}
+
+// WriteJSON writes the records as an indented JSON array
+func WriteJSON(w io.Writer, records []Record) error {
+	if records == nil {
+		records = []Record{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(records)
+}
+
+// WriteNDJSON writes one JSON object per line
+func WriteNDJSON(w io.Writer, records []Record) error {
+	encoder := json.NewEncoder(w)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CSVListSeparator joins multi-select values in a single CSV column
+const CSVListSeparator = "; "
+
+// csvColumns are the columns before the element values
+var csvColumns = []string{"hash", "author", "email", "date", "subject"}
+
+// elementColumn names the CSV column for an element. Elements named
+// like one of the other columns get the prefix "values.", as in JSON.
+func elementColumn(name string) string {
+	if slices.Contains(csvColumns, name) || name == "parsed_cleanly" {
+		return "values." + name
+	}
+	return name
+}
+
+// WriteCSV writes a header row and then one row per record, with a
+// column for each element in config order
+func WriteCSV(w io.Writer, cfg *config.Config, records []Record) error {
+	elements := exportedElements(cfg)
+	header := slices.Clone(csvColumns)
+	for _, elem := range elements {
+		header = append(header, elementColumn(elem.Name))
+	}
+	header = append(header, "parsed_cleanly")
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	for _, record := range records {
+		row := []string{record.Hash, record.Author, record.Email, record.Date.Format(time.RFC3339), record.Subject}
+		for _, elem := range elements {
+			switch value := record.Values[elem.Name].(type) {
+			case []string:
+				row = append(row, strings.Join(value, CSVListSeparator))
+			case string:
+				row = append(row, value)
+			default:
+				row = append(row, "")
+			}
+		}
+		row = append(row, strconv.FormatBool(record.ParsedCleanly))
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,97 @@
+package history
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"git-com/config"
+)
+
+func TestNewRecord(t *testing.T) {
+	cfg := loadTestConfig(t)
+	cfg.Elements = append(cfg.Elements, config.Element{
+		Name:        "sections",
+		Type:        config.TypeMultiSelect,
+		Destination: config.DestBody,
+		RecordAs:    config.RecordAsJoinedString,
+		Options:     []string{"ui", "core"},
+		AllowEmpty:  boolPtr(true),
+	})
+	commits := testCommits("fix: handle empty input\n\nui, core", "tidied up")
+	commits[0].Author.Name = "Ada"
+
+	record := NewRecord(cfg, commits[0])
+	if !record.ParsedCleanly || record.Author != "Ada" {
+		t.Errorf("record = %+v", record)
+	}
+	if got := record.Values["sections"]; !reflect.DeepEqual(got, []string{"ui", "core"}) {
+		t.Errorf("sections = %#v", got)
+	}
+	if got := record.Values["ticket"]; got != "" {
+		t.Errorf("ticket = %#v, want empty", got)
+	}
+
+	unparsed := NewRecord(cfg, commits[1])
+	if unparsed.ParsedCleanly {
+		t.Error("expected a message that doesn't match the config to be flagged")
+	}
+	if got := unparsed.Values["sections"]; !reflect.DeepEqual(got, []string{}) {
+		t.Errorf("sections = %#v, want an empty list", got)
+	}
+}
+
+func TestWriteRecords(t *testing.T) {
+	cfg := loadTestConfig(t)
+	var records []Record
+	for _, c := range testCommits("fix: one", "feat: two") {
+		records = append(records, NewRecord(cfg, c))
+	}
+
+	var ndjson bytes.Buffer
+	if err := WriteNDJSON(&ndjson, records); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(ndjson.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines of NDJSON", len(lines))
+	}
+	var decoded Record
+	if err := json.Unmarshal([]byte(lines[1]), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Values["type"] != "feat" {
+		t.Errorf("values = %v", decoded.Values)
+	}
+
+	var empty bytes.Buffer
+	if err := WriteJSON(&empty, nil); err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(empty.String()) != "[]" {
+		t.Errorf("WriteJSON(nil) = %q", empty.String())
+	}
+
+	var data bytes.Buffer
+	if err := WriteCSV(&data, cfg, records); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&data).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantHeader := []string{"hash", "author", "email", "date", "subject", "type", "values.subject", "description", "ticket", "parsed_cleanly"}
+	if !reflect.DeepEqual(rows[0], wantHeader) {
+		t.Errorf("header = %v", rows[0])
+	}
+	if len(rows) != 3 || rows[1][5] != "fix" || rows[1][6] != "one" || rows[1][9] != "true" {
+		t.Errorf("rows = %v", rows)
+	}
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
@@ -0,0 +1,164 @@
+package history
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"text/tabwriter"
+	"unicode/utf8"
+
+	"git-com/commit"
+	"git-com/config"
+	"git-com/message"
+)
+
+// Count is how many commits had a value, or a problem
+type Count struct {
+	Value   string  `json:"value"`
+	Count   int     `json:"count"`
+	Percent float64 `json:"percent"` // of all commits
+}
+
+// ElementStats is how often each option of a select or multi-select
+// element was chosen
+type ElementStats struct {
+	Name   string   `json:"name"`
+	Values []Count  `json:"values"` // most used first
+	Unused []string `json:"unused"` // options nobody chose, in config order
+}
+
+// Stats describes how consistently commits follow the config
+type Stats struct {
+	Commits            int            `json:"commits"`
+	Passing            int            `json:"passing"` // commits Lint found no problems with
+	PassRate           float64        `json:"pass_rate"`
+	AverageTitleLength float64        `json:"average_title_length"`
+	Elements           []ElementStats `json:"elements"`
+	Failures           []Count        `json:"failures"` // most common first
+}
+
+// BuildStats lints every commit and tallies the results
+func BuildStats(cfg *config.Config, commits []commit.Summary) *Stats {
+	stats := &Stats{Commits: len(commits), Elements: []ElementStats{}, Failures: []Count{}}
+	chosen := map[string]map[string]int{}
+	failures := map[string]int{}
+	titleLength := 0
+
+	for _, c := range commits {
+		values, problems := message.Lint(cfg, c.Message)
+		if len(problems) == 0 {
+			stats.Passing++
+		}
+		for _, problem := range problems {
+			failures[problem]++
+		}
+		titleLength += utf8.RuneCountInString(c.Subject)
+
+		for _, elem := range selectElements(cfg) {
+			if chosen[elem.Name] == nil {
+				chosen[elem.Name] = map[string]int{}
+			}
+			for _, selection := range elem.Selections(values[elem.Name]) {
+				chosen[elem.Name][selection]++
+			}
+		}
+	}
+
+	if stats.Commits > 0 {
+		stats.PassRate = percent(stats.Passing, stats.Commits)
+		stats.AverageTitleLength = float64(titleLength) / float64(stats.Commits)
+	}
+
+	for _, elem := range selectElements(cfg) {
+		elemStats := ElementStats{Name: elem.Name, Values: []Count{}, Unused: []string{}}
+		for _, option := range elem.Options {
+			if chosen[elem.Name][option] == 0 {
+				elemStats.Unused = append(elemStats.Unused, option)
+			}
+		}
+		elemStats.Values = counts(chosen[elem.Name], stats.Commits, elem.Options)
+		stats.Elements = append(stats.Elements, elemStats)
+	}
+	stats.Failures = counts(failures, stats.Commits, nil)
+	return stats
+}
+
+// selectElements are the elements whose values are worth counting
+func selectElements(cfg *config.Config) []config.Element {
+	var elements []config.Element
+	for _, elem := range cfg.Elements {
+		elemType := config.GetEffectiveType(elem)
+		if elemType == config.TypeSelect || elemType == config.TypeMultiSelect {
+			elements = append(elements, elem)
+		}
+	}
+	return elements
+}
+
+// counts sorts tallies by count, breaking ties by order and then by value
+func counts(tallies map[string]int, total int, order []string) []Count {
+	result := []Count{}
+	for value, count := range tallies {
+		result = append(result, Count{Value: value, Count: count, Percent: percent(count, total)})
+	}
+	slices.SortFunc(result, func(a, b Count) int {
+		if a.Count != b.Count {
+			return b.Count - a.Count
+		}
+		ai, bi := slices.Index(order, a.Value), slices.Index(order, b.Value)
+		if ai != bi {
+			// values that aren't options go after the ones that are
+			if ai < 0 || bi < 0 {
+				return bi - ai
+			}
+			return ai - bi
+		}
+		return cmp.Compare(a.Value, b.Value)
+	})
+	return result
+}
+
+func percent(count, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(count) * 100 / float64(total)
+}
+
+// MaxFailures is how many of the most common failures WriteTable lists
+const MaxFailures = 10
+
+// WriteTable writes the stats as aligned text for the terminal
+func (s *Stats) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "Commits\t%d\n", s.Commits)
+	fmt.Fprintf(tw, "Follow the config\t%d\t%.1f%%\n", s.Passing, s.PassRate)
+	fmt.Fprintf(tw, "Average title length\t%.1f characters\n", s.AverageTitleLength)
+
+	for _, elem := range s.Elements {
+		fmt.Fprintf(tw, "\n%s\n", elem.Name)
+		for _, value := range elem.Values {
+			fmt.Fprintf(tw, "  %s\t%d\t%.1f%%\n", value.Value, value.Count, value.Percent)
+		}
+		for _, option := range elem.Unused {
+			fmt.Fprintf(tw, "  %s\t0\tnever used\n", option)
+		}
+	}
+
+	if len(s.Failures) > 0 {
+		fmt.Fprintf(tw, "\nMost common problems\n")
+		for _, failure := range s.Failures[:min(len(s.Failures), MaxFailures)] {
+			fmt.Fprintf(tw, "  %s\t%d\t%.1f%%\n", failure.Value, failure.Count, failure.Percent)
+		}
+	}
+	return tw.Flush()
+}
+
+// WriteJSON writes the stats as indented JSON
+func (s *Stats) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(s)
+}
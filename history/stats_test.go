@@ -0,0 +1,58 @@
+package history
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBuildStats(t *testing.T) {
+	cfg := loadTestConfig(t)
+	stats := BuildStats(cfg, testCommits(
+		"fix: one",
+		"fix: two",
+		"feat: three",
+		"made some changes",
+	))
+
+	if stats.Commits != 4 || stats.Passing != 3 || stats.PassRate != 75 {
+		t.Errorf("passing = %d of %d (%.1f%%)", stats.Passing, stats.Commits, stats.PassRate)
+	}
+	if stats.AverageTitleLength != 11 {
+		t.Errorf("average title length = %v, want 11", stats.AverageTitleLength)
+	}
+
+	if len(stats.Elements) != 1 {
+		t.Fatalf("elements = %+v", stats.Elements)
+	}
+	typeStats := stats.Elements[0]
+	want := []Count{{"fix", 2, 50}, {"feat", 1, 25}}
+	if !reflect.DeepEqual(typeStats.Values, want) {
+		t.Errorf("values = %+v, want %+v", typeStats.Values, want)
+	}
+	if !reflect.DeepEqual(typeStats.Unused, []string{"docs", "feat!", "fix!"}) {
+		t.Errorf("unused = %v", typeStats.Unused)
+	}
+
+	if len(stats.Failures) == 0 || stats.Failures[0].Count != 1 {
+		t.Errorf("failures = %+v", stats.Failures)
+	}
+
+	var table bytes.Buffer
+	if err := stats.WriteTable(&table); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"75.0%", "never used", "title doesn't match the config"} {
+		if !strings.Contains(table.String(), want) {
+			t.Errorf("table doesn't contain %q:\n%s", want, table.String())
+		}
+	}
+}
+
+func TestBuildStats_NoCommits(t *testing.T) {
+	stats := BuildStats(loadTestConfig(t), nil)
+	if stats.Commits != 0 || stats.PassRate != 0 || len(stats.Failures) != 0 {
+		t.Errorf("stats = %+v", stats)
+	}
+}
@@ -0,0 +1,37 @@
+package history
+
+import (
+	"strings"
+
+	"git-com/commit"
+	"git-com/config"
+	"git-com/message"
+)
+
+// MaxSuggestions is the most values Suggestions returns
+const MaxSuggestions = 50
+
+// Suggestions returns the values elem had in commits, most recent first
+// and without repeats, to offer as completions. Only commits whose
+// author has authorEmail are used, unless it's empty. Messages that
+// don't match the config are skipped, since their values can't be trusted.
+func Suggestions(cfg *config.Config, elem config.Element, commits []commit.Summary, authorEmail string) []string {
+	var suggestions []string
+	seen := map[string]bool{}
+	for _, c := range commits {
+		if authorEmail != "" && !strings.EqualFold(c.Author.Email, authorEmail) {
+			continue
+		}
+		values, clean := message.ParseChecked(cfg, c.Message)
+		value := strings.TrimSpace(values[elem.Name])
+		if !clean || value == "" || seen[value] {
+			continue
+		}
+		seen[value] = true
+		suggestions = append(suggestions, value)
+		if len(suggestions) == MaxSuggestions {
+			break
+		}
+	}
+	return suggestions
+}
@@ -0,0 +1,33 @@
+package history
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSuggestions(t *testing.T) {
+	cfg := loadTestConfig(t)
+	commits := testCommits(
+		"fix: handle empty input\n\nTicket: 12",
+		"feat: add dark mode\n\nTicket: 14",
+		"not a conventional message",
+		"fix: handle empty input\n\nTicket: 12",
+		"docs: explain templates",
+	)
+	for i := range commits {
+		commits[i].Author.Email = "me@example.com"
+	}
+	commits[1].Author.Email = "someone@example.com"
+
+	ticket, subject := cfg.Elements[3], cfg.Elements[1]
+
+	if got := Suggestions(cfg, subject, commits, ""); !reflect.DeepEqual(got, []string{"handle empty input", "add dark mode", "explain templates"}) {
+		t.Errorf("Suggestions(subject, everyone) = %q", got)
+	}
+	if got := Suggestions(cfg, subject, commits, "ME@example.com"); !reflect.DeepEqual(got, []string{"handle empty input", "explain templates"}) {
+		t.Errorf("Suggestions(subject, mine) = %q", got)
+	}
+	if got := Suggestions(cfg, ticket, commits, "me@example.com"); !reflect.DeepEqual(got, []string{"12"}) {
+		t.Errorf("Suggestions(ticket, mine) = %q", got)
+	}
+}
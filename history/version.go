@@ -0,0 +1,162 @@
+package history
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"git-com/commit"
+	"git-com/config"
+)
+
+// Version is a semantic version, as found in a tag like v1.2.3
+type Version struct {
+	Prefix              string // "v" or ""
+	Major, Minor, Patch int
+}
+
+// versionPattern matches release tags. Pre-releases like v1.2.3-rc.1
+// aren't releases, so they're ignored.
+var versionPattern = regexp.MustCompile(`^(v?)(\d+)\.(\d+)\.(\d+)$`)
+
+// ParseVersion parses a tag like v1.2.3 or 1.2.3
+func ParseVersion(tag string) (Version, bool) {
+	match := versionPattern.FindStringSubmatch(tag)
+	if match == nil {
+		return Version{}, false
+	}
+	major, _ := strconv.Atoi(match[2])
+	minor, _ := strconv.Atoi(match[3])
+	patch, _ := strconv.Atoi(match[4])
+	return Version{Prefix: match[1], Major: major, Minor: minor, Patch: patch}, true
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%s%d.%d.%d", v.Prefix, v.Major, v.Minor, v.Patch)
+}
+
+// Less reports whether v is an earlier version than other
+func (v Version) Less(other Version) bool {
+	if v.Major != other.Major {
+		return v.Major < other.Major
+	}
+	if v.Minor != other.Minor {
+		return v.Minor < other.Minor
+	}
+	return v.Patch < other.Patch
+}
+
+// Bump returns the version after a bump
+func (v Version) Bump(b Bump) Version {
+	switch b {
+	case BumpMajor:
+		return Version{Prefix: v.Prefix, Major: v.Major + 1}
+	case BumpMinor:
+		return Version{Prefix: v.Prefix, Major: v.Major, Minor: v.Minor + 1}
+	case BumpPatch:
+		return Version{Prefix: v.Prefix, Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1}
+	default:
+		return v
+	}
+}
+
+// LatestVersion returns the highest version among tags, and its tag
+func LatestVersion(tags []string) (Version, string, bool) {
+	var latest Version
+	latestTag := ""
+	for _, tag := range tags {
+		if v, ok := ParseVersion(tag); ok && (latestTag == "" || latest.Less(v)) {
+			latest, latestTag = v, tag
+		}
+	}
+	return latest, latestTag, latestTag != ""
+}
+
+// Bump is how much a version has to change. Larger bumps win.
+type Bump int
+
+const (
+	BumpNone Bump = iota
+	BumpPatch
+	BumpMinor
+	BumpMajor
+)
+
+func (b Bump) String() string {
+	switch b {
+	case BumpPatch:
+		return config.BumpPatch
+	case BumpMinor:
+		return config.BumpMinor
+	case BumpMajor:
+		return config.BumpMajor
+	default:
+		return "none"
+	}
+}
+
+// parseBump reads a version-bump value
+func parseBump(value string) Bump {
+	switch value {
+	case config.BumpMajor:
+		return BumpMajor
+	case config.BumpMinor:
+		return BumpMinor
+	case config.BumpPatch:
+		return BumpPatch
+	default:
+		return BumpNone
+	}
+}
+
+// EntryBump returns the bump a commit calls for: major if it's a breaking
+// change, otherwise the largest version-bump of its selected options
+func EntryBump(cfg *config.Config, entry Entry) Bump {
+	if entry.Breaking {
+		return BumpMajor
+	}
+	bump := BumpNone
+	for _, elem := range cfg.Elements {
+		for _, selection := range elem.Selections(entry.Values[elem.Name]) {
+			bump = max(bump, parseBump(elem.VersionBump[selection]))
+		}
+	}
+	return bump
+}
+
+// Release is the next version and why
+type Release struct {
+	Previous string  // the tag it follows, "" if there wasn't one
+	Current  Version // the version in Previous, or 0.0.0
+	Next     Version
+	Bump     Bump
+	Reasons  []Entry // the commits that call for Bump
+}
+
+// NextVersion works out the version that follows previousTag, given the
+// commits made since. Without a previous tag it starts from v0.0.0.
+func NextVersion(cfg *config.Config, previousTag string, commits []commit.Summary) (*Release, error) {
+	release := &Release{Previous: previousTag, Current: Version{Prefix: "v"}}
+	if previousTag != "" {
+		current, ok := ParseVersion(previousTag)
+		if !ok {
+			return nil, fmt.Errorf("%s is not a version like v1.2.3", previousTag)
+		}
+		release.Current = current
+	}
+
+	for _, c := range commits {
+		entry := NewEntry(cfg, c)
+		bump := EntryBump(cfg, entry)
+		if bump > release.Bump {
+			release.Bump = bump
+			release.Reasons = nil
+		}
+		if bump == release.Bump && bump != BumpNone {
+			release.Reasons = append(release.Reasons, entry)
+		}
+	}
+
+	release.Next = release.Current.Bump(release.Bump)
+	return release, nil
+}
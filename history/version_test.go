@@ -0,0 +1,46 @@
+package history
+
+import "testing"
+
+func TestLatestVersion(t *testing.T) {
+	v, tag, found := LatestVersion([]string{"v1.2.3", "v1.10.0", "v2.0.0-rc.1", "release", "1.9.9"})
+	if !found || tag != "v1.10.0" || v != (Version{Prefix: "v", Major: 1, Minor: 10}) {
+		t.Errorf("LatestVersion() = %v, %q, %v", v, tag, found)
+	}
+
+	if _, _, found := LatestVersion([]string{"release"}); found {
+		t.Error("expected no version among non-version tags")
+	}
+}
+
+func TestNextVersion(t *testing.T) {
+	cfg := loadTestConfig(t)
+	tests := []struct {
+		name     string
+		previous string
+		messages []string
+		want     string
+		reasons  int
+	}{
+		{"patch", "v1.2.3", []string{"fix: a", "docs: b"}, "v1.2.4", 1},
+		{"minor wins over patch", "v1.2.3", []string{"fix: a", "feat: b", "feat: c"}, "v1.3.0", 2},
+		{"breaking is major", "1.2.3", []string{"feat: a", "fix!: b"}, "2.0.0", 1},
+		{"nothing to release", "v1.2.3", []string{"docs: a"}, "v1.2.3", 0},
+		{"no previous tag", "", []string{"feat: a"}, "v0.1.0", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			release, err := NextVersion(cfg, tt.previous, testCommits(tt.messages...))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := release.Next.String(); got != tt.want {
+				t.Errorf("Next = %s, want %s", got, tt.want)
+			}
+			if len(release.Reasons) != tt.reasons {
+				t.Errorf("got %d reasons, want %d", len(release.Reasons), tt.reasons)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+// Package hook lets git-com run from inside `git commit`, either as a
+// prepare-commit-msg hook or as git's editor.
+package hook
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// marker identifies hook scripts and editor settings that we installed
+const marker = "# installed by git-com"
+
+// hookName is the git hook we install
+const hookName = "prepare-commit-msg"
+
+// EditorCommand is what core.editor is set to by InstallEditor
+const EditorCommand = "git com hook --editor"
+
+// ErrHookExists indicates there's already a prepare-commit-msg hook
+// that git-com didn't install
+var ErrHookExists = errors.New("a " + hookName + " hook already exists")
+
+// script is the prepare-commit-msg hook
+const script = "#!/bin/sh\n" + marker + "\nexec git com hook \"$@\"\n"
+
+// Sources of the commit message (the second argument to prepare-commit-msg)
+// that already have a message we shouldn't replace.
+var skippedSources = map[string]bool{
+	"message": true, // -m or -F
+	"merge":   true,
+	"squash":  true,
+	"commit":  true, // -c, -C, or --amend
+}
+
+// ShouldPrompt returns true if the interactive flow should run
+// for a message coming from source
+func ShouldPrompt(source string) bool {
+	return !skippedSources[source]
+}
+
+// HookPath returns where the prepare-commit-msg hook lives,
+// honoring core.hooksPath
+func HookPath() (string, error) {
+	path, err := gitPath("hooks/" + hookName)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Abs(path)
+}
+
+// Install writes the prepare-commit-msg hook.
+// Returns ErrHookExists if a hook we didn't write is in the way,
+// unless force is true.
+func Install(force bool) (string, error) {
+	path, err := HookPath()
+	if err != nil {
+		return "", err
+	}
+	if !force {
+		if existing, err := os.ReadFile(path); err == nil && !isOurs(existing) {
+			return path, ErrHookExists
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return path, err
+	}
+	return path, os.WriteFile(path, []byte(script), 0755)
+}
+
+// Uninstall removes the prepare-commit-msg hook if we installed it.
+// Returns false if there was nothing of ours to remove.
+func Uninstall() (bool, error) {
+	path, err := HookPath()
+	if err != nil {
+		return false, err
+	}
+	existing, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if !isOurs(existing) {
+		return false, ErrHookExists
+	}
+	return true, os.Remove(path)
+}
+
+// InstallEditor sets core.editor for this repository so that
+// git opens git-com whenever it wants a commit message
+func InstallEditor() error {
+	return exec.Command("git", "config", "--local", "core.editor", EditorCommand).Run()
+}
+
+// UninstallEditor unsets core.editor if it points at git-com.
+// Returns false if it didn't.
+func UninstallEditor() (bool, error) {
+	current, _ := exec.Command("git", "config", "--local", "core.editor").Output()
+	if strings.TrimSpace(string(current)) != EditorCommand {
+		return false, nil
+	}
+	return true, exec.Command("git", "config", "--local", "--unset", "core.editor").Run()
+}
+
+// isOurs returns true if the hook script was written by Install
+func isOurs(contents []byte) bool {
+	return strings.Contains(string(contents), marker)
+}
+
+// gitPath resolves a path inside the .git directory
+func gitPath(name string) (string, error) {
+	output, err := exec.Command("git", "rev-parse", "--git-path", name).Output()
+	if err != nil {
+		return "", errors.New("not in a git repository")
+	}
+	return strings.TrimSpace(string(output)), nil
+}
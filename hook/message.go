@@ -0,0 +1,113 @@
+package hook
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// scissors marks the start of the diff git includes with commit.verbose.
+// Everything after it is ignored by git.
+const scissors = "# ------------------------ >8 ------------------------"
+
+// MessageFile is a commit message file handed to us by git
+type MessageFile struct {
+	Path     string
+	Message  string // the lines git will use
+	Comments string // comment lines and anything after the scissors line
+}
+
+// ReadMessageFile loads and splits a commit message file
+func ReadMessageFile(path string) (*MessageFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var message, comments []string
+	afterScissors := false
+	for _, line := range strings.SplitAfter(string(data), "\n") {
+		if strings.TrimRight(line, "\r\n") == scissors {
+			afterScissors = true
+		}
+		if afterScissors || strings.HasPrefix(line, "#") {
+			comments = append(comments, line)
+		} else {
+			message = append(message, line)
+		}
+	}
+
+	return &MessageFile{
+		Path:     path,
+		Message:  strings.TrimSpace(strings.Join(message, "")),
+		Comments: strings.Join(comments, ""),
+	}, nil
+}
+
+// HasMessage returns true if the file already contains a message
+func (f *MessageFile) HasMessage() bool {
+	return f.Message != ""
+}
+
+// Write replaces the message, keeping git's comments below it
+func (f *MessageFile) Write(message string) error {
+	f.Message = message
+	contents := message + "\n"
+	if f.Comments != "" {
+		contents += "\n" + f.Comments
+	}
+	return os.WriteFile(f.Path, []byte(contents), 0644)
+}
+
+// IsCommitMessage returns true if path is the file git uses for commit
+// messages. As the editor we're also handed rebase todo lists, tag
+// messages, and the like, which we leave to a real editor.
+func IsCommitMessage(path string) bool {
+	return filepath.Base(path) == "COMMIT_EDITMSG"
+}
+
+// MergeInProgress returns true if the commit being made concludes a merge
+// or squash, which come with their own messages
+func MergeInProgress() bool {
+	for _, name := range []string{"MERGE_HEAD", "SQUASH_MSG"} {
+		path, err := gitPath(name)
+		if err != nil {
+			continue
+		}
+		if _, err := os.Stat(path); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// HasTerminal returns true if we can talk to the user.
+// Commit buttons in IDEs run hooks without one.
+func HasTerminal() bool {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return false
+	}
+	tty.Close()
+	return true
+}
+
+// RunEditor opens path in the user's real editor, for files we don't handle.
+// It uses $GIT_COM_EDITOR, $VISUAL, or $EDITOR, falling back to vi.
+func RunEditor(path string) error {
+	editor := "vi"
+	for _, name := range []string{"GIT_COM_EDITOR", "VISUAL", "EDITOR"} {
+		if value := os.Getenv(name); value != "" {
+			editor = value
+			break
+		}
+	}
+
+	// editors are shell commands, e.g. "code --wait"
+	cmd := exec.Command("sh", "-c", editor+` "$@"`, editor, path)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
@@ -0,0 +1,98 @@
+package hook
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestMessageFile_KeepsComments(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "COMMIT_EDITMSG")
+	original := "\n# Please enter the commit message for your changes.\n#\n" +
+		scissors + "\ndiff --git a/x b/x\n"
+	if err := os.WriteFile(path, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := ReadMessageFile(path)
+	if err != nil {
+		t.Fatalf("ReadMessageFile() error = %v", err)
+	}
+	if file.HasMessage() {
+		t.Errorf("HasMessage() = true, want false; message = %q", file.Message)
+	}
+
+	if err := file.Write("title\n\nbody"); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	want := "title\n\nbody\n\n# Please enter the commit message for your changes.\n#\n" +
+		scissors + "\ndiff --git a/x b/x\n"
+	if string(data) != want {
+		t.Errorf("file = %q, want %q", data, want)
+	}
+}
+
+func TestMessageFile_HasMessage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "COMMIT_EDITMSG")
+	if err := os.WriteFile(path, []byte("existing title\n\n# comment\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	file, err := ReadMessageFile(path)
+	if err != nil {
+		t.Fatalf("ReadMessageFile() error = %v", err)
+	}
+	if file.Message != "existing title" {
+		t.Errorf("Message = %q, want %q", file.Message, "existing title")
+	}
+}
+
+func TestShouldPrompt(t *testing.T) {
+	tests := map[string]bool{
+		"":         true,
+		"template": true,
+		"message":  false,
+		"merge":    false,
+		"squash":   false,
+		"commit":   false,
+	}
+	for source, want := range tests {
+		if got := ShouldPrompt(source); got != want {
+			t.Errorf("ShouldPrompt(%q) = %v, want %v", source, got, want)
+		}
+	}
+}
+
+func TestInstallUninstall(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := exec.Command("git", "init", "-q").Run(); err != nil {
+		t.Skip("git not available")
+	}
+
+	path, err := Install(false)
+	if err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	if _, err := Install(false); err != nil {
+		t.Errorf("reinstalling our own hook should succeed, got %v", err)
+	}
+
+	removed, err := Uninstall()
+	if err != nil || !removed {
+		t.Fatalf("Uninstall() = %v, %v", removed, err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("hook still exists after Uninstall()")
+	}
+
+	if err := os.WriteFile(path, []byte("#!/bin/sh\necho mine\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Install(false); err != ErrHookExists {
+		t.Errorf("Install() over someone else's hook error = %v, want ErrHookExists", err)
+	}
+	if _, err := Uninstall(); err != ErrHookExists {
+		t.Errorf("Uninstall() of someone else's hook error = %v, want ErrHookExists", err)
+	}
+}
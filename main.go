@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 
+	"git-com/coauthors"
 	"git-com/commit"
 	"git-com/config"
 	"git-com/output"
@@ -16,50 +17,166 @@ import (
 func main() {
 	// Parse command-line flags
 	amendFlag := flag.Bool("amend", false, "Amend the last commit")
+	printFlag := flag.Bool("print", false, "Print the message to stdout instead of committing")
+	outputFlag := flag.String("output", "", "Write the message to `FILE` instead of committing")
+	var allFlag bool
+	flag.BoolVar(&allFlag, "a", false, "Stage all changes to tracked files, like git commit -a")
+	flag.BoolVar(&allFlag, "all", false, "Stage all changes to tracked files, like git commit -a")
+	var fixupFlag, squashFlag, rewordFlag revFlag
+	flag.Var(&fixupFlag, "fixup", "Create a fixup! commit for `REV` (choose from recent commits if omitted)")
+	flag.Var(&squashFlag, "squash", "Create a squash! commit for `REV` (choose from recent commits if omitted)")
+	flag.Var(&rewordFlag, "reword", "Change the message of `REV` and rewrite the commits after it")
+	var commitOpts commit.Options
+	flag.StringVar(&commitOpts.Author, "author", "", "Override the commit author, given as `\"Name <email>\"`")
+	flag.StringVar(&commitOpts.Date, "date", "", "Override the author `DATE`")
+	flag.BoolVar(&commitOpts.AllowEmpty, "allow-empty", false, "Allow a commit that doesn't change any files, like git commit --allow-empty")
+	flag.BoolVar(&commitOpts.ResetAuthor, "reset-author", false, "With --amend, make yourself the author and reset the author date")
 	flag.Parse()
+	args := claimRevArgument(flag.Args(), &fixupFlag, &squashFlag, &rewordFlag)
 
-	// Load configuration from git root
-	cfg, err := config.LoadConfig()
-	if err != nil {
-		if errors.Is(err, config.ErrConfigNotFound) {
-			output.PrintError("Config file .git-com.yaml not found in git repository root")
-		} else if errors.Is(err, config.ErrNotInGitRepo) {
-			output.PrintError("Not in a git repository")
-		} else {
-			output.PrintError("Error loading config: " + err.Error())
-		}
-		os.Exit(1)
+	// With --print or --output we only build the message
+	messageOnly := *printFlag || *outputFlag != ""
+
+	// Subcommands (e.g. `git com init`) handle everything themselves
+	if len(args) > 0 {
+		runSubcommand(args[0], args[1:])
+		return
 	}
 
+	verifySingleMode(*amendFlag, fixupFlag.set, squashFlag.set, rewordFlag.set)
+	verifyCommitOptions(commitOpts, *amendFlag, rewordFlag.set, messageOnly)
+
+	// Load configuration from git root
+	cfg := loadConfigOrOfferInit()
+
 	// Validate configuration
 	if !config.ValidateConfig(cfg) {
 		os.Exit(1)
 	}
 
 	// Determine if we are creating a new commit or amending
-	creatingNewCommit := !*amendFlag
+	creatingNewCommit := !*amendFlag && !rewordFlag.set
 
-	// If amending, check that there are commits to amend
-	if !creatingNewCommit {
+	// If amending or targeting an earlier commit, check that there are commits
+	if *amendFlag || fixupFlag.set || squashFlag.set || rewordFlag.set {
 		verifyHasCommitsToAmend()
 	}
 
+	// The commit that --fixup, --squash, or --reword refers to
+	var target commit.Summary
+	switch {
+	case fixupFlag.set:
+		target = chooseTargetCommit(fixupFlag)
+	case squashFlag.set:
+		target = chooseTargetCommit(squashFlag)
+	case rewordFlag.set:
+		target = chooseTargetCommit(rewordFlag)
+	}
+
 	// Initialize oldCommitMessage as nil
 	var oldCommitMessage *string
 
-	// If amending, check for multiline-text elements with destination=body
-	// and retrieve the last commit's body if such elements exist
-	if !creatingNewCommit && hasMultilineTextBodyElement(cfg) {
+	// If amending or rewording, check for multiline-text elements with
+	// destination=body and retrieve the old commit's body if such elements exist
+	if hasMultilineTextBodyElement(cfg) {
 		// body is already nil if empty, so just assign it
-		oldCommitMessage = getOldCommitMessageBody()
+		if *amendFlag {
+			oldCommitMessage = getOldCommitMessageBody()
+		} else if rewordFlag.set {
+			oldCommitMessage = getCommitBody(target)
+		}
+	}
+
+	if allFlag && !messageOnly {
+		stageTrackedChanges()
 	}
 
 	// Check if there are staged files (only for new commits, not amends)
-	if creatingNewCommit {
+	if creatingNewCommit && !messageOnly && !commitOpts.AllowEmpty {
 		verifyStagedFiles()
 	}
 
-	// Process all elements
+	// Let text prompts show what's being committed
+	changedFiles := 0
+	var diffErr error
+	if !rewordFlag.set {
+		changedFiles, diffErr = provideStagedDiff()
+	}
+	if *amendFlag && !messageOnly && diffErr == nil && changedFiles == 0 {
+		output.PrintToStderr("Nothing is staged, so only the message of the last commit will change.")
+	}
+
+	// Process all elements. A fixup! commit's message comes from its target.
+	var result *prompt.Result
+	if fixupFlag.set {
+		result = fixupResult(target)
+	} else {
+		result = processElements(cfg, oldCommitMessage)
+	}
+	if squashFlag.set {
+		result = squashResult(target, result)
+	}
+
+	// Clear screen and show commit preview
+	showPreview(result)
+	if !messageOnly {
+		output.PrintToStderr(describeContentChange(creatingNewCommit, changedFiles, diffErr) + "\n")
+	}
+
+	// Confirm with user
+	// exits if they don't accept it.
+	performFinalConfirmation("Is this good?")
+
+	if messageOnly {
+		writeMessage(*printFlag, *outputFlag, result)
+		os.Exit(0)
+	}
+
+	// Create, amend, or reword the commit based on the flags
+	if rewordFlag.set {
+		rewordCommit(target, result)
+	} else {
+		commitOrAmend(creatingNewCommit, result, commitOpts)
+	}
+
+	os.Exit(0)
+}
+
+// runSubcommand dispatches to the named subcommand and exits
+func runSubcommand(name string, args []string) {
+	switch name {
+	case "init":
+		runInit(args)
+	case "config":
+		runConfigCommand(args)
+	case "options":
+		runOptionsCommand(args)
+	case "reword":
+		runReword(args)
+	case "changelog":
+		runChangelog(args)
+	case "next-version":
+		runNextVersion(args)
+	case "log":
+		runLog(args)
+	case "stats":
+		runStats(args)
+	case "hook":
+		runHook(args)
+	case "install-hook":
+		runInstallHook(args)
+	case "uninstall-hook":
+		runUninstallHook(args)
+	default:
+		output.PrintError("Unknown command: " + name)
+		os.Exit(64)
+	}
+	os.Exit(0)
+}
+
+// prompts for every element
+// exits silently if the user aborts, or with an error if there was a problem
+func processElements(cfg *config.Config, oldCommitMessage *string) *prompt.Result {
 	result, err := prompt.ProcessElements(cfg, oldCommitMessage)
 	if err != nil {
 		if errors.Is(err, prompt.ErrUserAborted) {
@@ -69,24 +186,30 @@ func main() {
 		output.PrintError("Error processing input: " + err.Error())
 		os.Exit(1)
 	}
+	return result
+}
 
-	// Clear screen and show commit preview
-	prompt.ClearScreen()
-	fmt.Fprintln(os.Stderr, result.Title)
-	if result.Body != "" {
-		fmt.Fprintln(os.Stderr)
-		fmt.Fprintln(os.Stderr, result.Body)
+// loads the config from the git root.
+// If there isn't one it offers to run `git com init`.
+// prints an error and exits if there was a problem
+func loadConfigOrOfferInit() *config.Config {
+	cfg, err := config.LoadConfig()
+	if err == nil {
+		return cfg
 	}
-	fmt.Fprintln(os.Stderr)
 
-	// Confirm with user
-	// exits if they don't accept it.
-	performFinalConfirmation("Is this good?")
-
-	// Create or amend the commit based on the flag
-	commitOrAmend(creatingNewCommit, result)
-
-	os.Exit(0)
+	if errors.Is(err, config.ErrConfigNotFound) {
+		output.PrintError("Config file .git-com.yaml not found in git repository root")
+		if offerInit() {
+			return createConfig(defaultConfigPath())
+		}
+	} else if errors.Is(err, config.ErrNotInGitRepo) {
+		output.PrintError("Not in a git repository")
+	} else {
+		output.PrintError("Error loading config: " + err.Error())
+	}
+	os.Exit(1)
+	return nil
 }
 
 // attempts to get the body of the last commit
@@ -113,18 +236,96 @@ func hasMultilineTextBodyElement(cfg *config.Config) bool {
 }
 
 // Create or amend the commit based on what the user indicated at launch.
-func commitOrAmend(creatingNewCommit bool, result *prompt.Result) {
+func commitOrAmend(creatingNewCommit bool, result *prompt.Result, opts commit.Options) {
 	if creatingNewCommit {
-		if err := commit.CreateCommit(result.Title, result.Body); err != nil {
+		if err := commit.CreateCommit(result.Title, result.Body, opts); err != nil {
 			output.PrintError("Error creating commit: " + err.Error())
 			os.Exit(1)
 		}
 	} else {
-		if err := commit.AmendCommit(result.Title, result.Body); err != nil {
+		if err := commit.AmendCommit(result.Title, result.Body, opts); err != nil {
 			output.PrintError("Error amending commit: " + err.Error())
 			os.Exit(1)
 		}
 	}
+	rememberCoAuthors(result)
+}
+
+// records who the commit was made with, so they're listed first next time.
+// It's only called once there's a commit, so previews and aborts don't count.
+func rememberCoAuthors(result *prompt.Result) {
+	if err := coauthors.RememberPairing(coauthors.CurrentUser().Email, result.CoAuthors); err != nil {
+		output.PrintWarningToStderr("Could not remember co-authors: " + err.Error())
+	}
+}
+
+// makes the staged changes available to text prompts
+// and returns the number of files they touch.
+// The diff is a nicety: when it can't be computed the prompts go
+// without it, and err says why.
+func provideStagedDiff() (int, error) {
+	diff, err := commit.GetStagedDiff()
+	if err != nil {
+		return 0, err
+	}
+	if len(diff.Files) == 0 {
+		return 0, nil
+	}
+	patch, err := diff.Patch(true)
+	if err != nil {
+		return len(diff.Files), nil
+	}
+	tui.SetDiffContext(&tui.DiffContext{Summary: diff.Summary(), Patch: patch})
+	return len(diff.Files), nil
+}
+
+// describes what committing will do to the repository's content,
+// for the preview. diffErr is why the staged changes couldn't be found.
+func describeContentChange(creatingNewCommit bool, changedFiles int, diffErr error) string {
+	if diffErr != nil {
+		return "Couldn't determine which files will change: " + diffErr.Error()
+	}
+	files := fmt.Sprintf("%d files", changedFiles)
+	if changedFiles == 1 {
+		files = "1 file"
+	}
+	switch {
+	case creatingNewCommit && changedFiles == 0:
+		return "This commit doesn't change any files."
+	case creatingNewCommit:
+		return "This commit changes " + files + "."
+	case changedFiles == 0:
+		return "Only the commit message will change."
+	default:
+		return "The commit message will change and " + files + " will be added to the commit."
+	}
+}
+
+// clears the screen and shows the message that will be committed
+func showPreview(result *prompt.Result) {
+	prompt.ClearScreen()
+	fmt.Fprintln(os.Stderr, result.Title)
+	if result.Body != "" {
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprintln(os.Stderr, result.Body)
+	}
+	fmt.Fprintln(os.Stderr)
+}
+
+// writes the commit message to stdout and/or a file
+// instead of committing.
+// prints an error and exits if there was a problem
+func writeMessage(toStdout bool, path string, result *prompt.Result) {
+	message := commit.BuildCommitMessage(result.Title, result.Body) + "\n"
+	if toStdout {
+		fmt.Print(message)
+	}
+	if path != "" {
+		if err := os.WriteFile(path, []byte(message), 0644); err != nil {
+			output.PrintError("Error writing message: " + err.Error())
+			os.Exit(1)
+		}
+	}
 }
 
 // tests if there are any commits.
@@ -138,25 +339,77 @@ func verifyHasCommitsToAmend() {
 		os.Exit(1)
 	}
 	if !hasCommits {
-		output.PrintWarning("There are no commits to amend.")
+		output.PrintWarningToStderr("There are no commits to amend.")
 		os.Exit(1)
 	}
 }
 
-// checks if the user has staged any files
-// prints warning and exits if they haven't.
+// checks if the user has staged any files.
+// If they haven't it offers to stage some.
+// prints warning and exits if nothing ends up staged.
 func verifyStagedFiles() {
 	hasStaged, err := commit.HasStagedFiles()
 	if err != nil {
 		output.PrintError("Error checking staged files: " + err.Error())
 		os.Exit(1)
 	}
-	if !hasStaged {
+	if !hasStaged && !offerToStageFiles() {
 		output.PrintWarningToStderr("You need to stage some files before we can commit.")
 		os.Exit(64)
 	}
 }
 
+// lets the user pick unstaged changes to stage.
+// returns false if there was nothing to pick or they didn't pick anything
+func offerToStageFiles() bool {
+	changes, err := commit.UnstagedChanges()
+	if err != nil {
+		output.PrintError("Error checking for changes: " + err.Error())
+		os.Exit(1)
+	}
+	if len(changes) == 0 {
+		return false
+	}
+
+	labels := make([]string, len(changes))
+	paths := make(map[string]string, len(changes))
+	for i, change := range changes {
+		labels[i] = change.String()
+		paths[labels[i]] = change.Path
+	}
+
+	selected, err := tui.Choose(labels, -1, "Nothing is staged. Which files should be committed?")
+	if err != nil {
+		if errors.Is(err, tui.ErrAborted) {
+			os.Exit(0)
+		}
+		output.PrintError("Error choosing files: " + err.Error())
+		os.Exit(1)
+	}
+	if len(selected) == 0 {
+		return false
+	}
+
+	toStage := make([]string, len(selected))
+	for i, label := range selected {
+		toStage[i] = paths[label]
+	}
+	if err := commit.StageFiles(toStage); err != nil {
+		output.PrintError("Error staging files: " + err.Error())
+		os.Exit(1)
+	}
+	return true
+}
+
+// stages all changes to tracked files for -a / --all.
+// prints an error and exits if there was a problem
+func stageTrackedChanges() {
+	if err := commit.StageTrackedChanges(); err != nil {
+		output.PrintError("Error staging changes: " + err.Error())
+		os.Exit(1)
+	}
+}
+
 // asks the user if they're ok with the commit message
 // they've created.
 // Exits if they're not.
@@ -0,0 +1,242 @@
+// Package message recovers element values from existing commit messages
+// so they can be used to pre-fill the prompts.
+package message
+
+import (
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"git-com/config"
+)
+
+// Parse splits message into values keyed by element name, by matching it
+// against the before-string and after-string of each element, or against
+// title-template and body-template when there are any.
+// Whitespace in the decorators is matched loosely since old messages are
+// rarely exact. Elements that can't be found are left out.
+//
+// When a section (title or body) doesn't match at all, the whole section
+// is given to its free-text element so nothing is lost.
+func Parse(cfg *config.Config, message string) map[string]string {
+	values, _ := ParseChecked(cfg, message)
+	return values
+}
+
+// ParseChecked is Parse, but also reports whether the message parsed
+// cleanly, i.e. Lint found no problems with it
+func ParseChecked(cfg *config.Config, message string) (map[string]string, bool) {
+	values, problems := Lint(cfg, message)
+	return values, len(problems) == 0
+}
+
+// Lint parses message like Parse and describes everything about it that
+// doesn't follow the config: sections that don't match, required
+// elements that are missing, and values that aren't one of the options.
+// Sections written with a title-template or body-template are matched
+// against the template (see templateReader).
+func Lint(cfg *config.Config, message string) (map[string]string, []string) {
+	title, body, _ := strings.Cut(strings.TrimSpace(message), "\n")
+	values := map[string]string{}
+	var problems []string
+	written := map[string]bool{}
+	sections := []struct {
+		dest config.Destination
+		text string
+	}{{config.DestTitle, title}, {config.DestBody, body}}
+	for _, section := range sections {
+		problem, names := lintSection(cfg, section.dest, strings.TrimSpace(section.text), values)
+		if problem != "" {
+			problems = append(problems, problem)
+		}
+		for _, name := range names {
+			written[name] = true
+		}
+	}
+
+	for _, elem := range cfg.Elements {
+		value := values[elem.Name]
+		if value == "" {
+			if written[elem.Name] && !elem.IsAllowEmpty() && !mayBeMissing(elem) {
+				problems = append(problems, "missing "+elem.Name)
+			}
+			continue
+		}
+		switch config.GetEffectiveType(elem) {
+		case config.TypeSelect, config.TypeMultiSelect:
+			for _, selection := range elem.Selections(value) {
+				if !slices.Contains(elem.Options, selection) {
+					problems = append(problems, elem.Name+" isn't one of the options")
+					break
+				}
+			}
+		}
+	}
+	return values, problems
+}
+
+// mayBeMissing returns true for elements that can be left out of a
+// message without anyone skipping them: co-authors when nobody else
+// worked on it, and computed values that came out empty
+func mayBeMissing(elem config.Element) bool {
+	switch config.GetEffectiveType(elem) {
+	case config.TypeCoAuthors, config.TypeComputed:
+		return true
+	}
+	return false
+}
+
+// lintSection parses the title or body into values. It returns what's
+// wrong with it, if anything, and the names of the elements it should
+// have, so they can be checked for. Elements of an unreadable template
+// aren't returned, since nothing can be said about them.
+func lintSection(cfg *config.Config, dest config.Destination, text string, values map[string]string) (problem string, names []string) {
+	elements := elementsFor(cfg, dest)
+	if cfg.Template(dest) == "" {
+		for _, elem := range elements {
+			names = append(names, elem.Name)
+		}
+		if !parseSection(elements, text, values) {
+			return string(dest) + " doesn't match the config", names
+		}
+		return "", names
+	}
+
+	key := config.TitleTemplateKey
+	if dest == config.DestBody {
+		key = config.BodyTemplateKey
+	}
+	reader, re, err := newTemplateReader(cfg, key, cfg.Template(dest))
+	if err != nil {
+		return key + " can't be read back (only {{.name}}, {{join}}, and {{if}} can be)", nil
+	}
+	if text == "" && len(reader.fields) == 0 {
+		return "", nil
+	}
+	if !reader.read(re, text, values) {
+		if elem, ok := freeTextElement(elements); ok && text != "" {
+			values[elem.Name] = text
+		}
+		return string(dest) + " doesn't match " + key, reader.names()
+	}
+	return "", reader.names()
+}
+
+// elementsFor returns the elements that write to dest, in order.
+// Confirmations never add anything to the message.
+func elementsFor(cfg *config.Config, dest config.Destination) []config.Element {
+	var elements []config.Element
+	for _, elem := range cfg.Elements {
+		if elem.Destination == dest && config.GetEffectiveType(elem) != config.TypeConfirmation {
+			elements = append(elements, elem)
+		}
+	}
+	return elements
+}
+
+// parseSection matches text against elements and records what it finds.
+// It returns false if text didn't match.
+func parseSection(elements []config.Element, text string, values map[string]string) bool {
+	if len(elements) == 0 || text == "" {
+		return text == ""
+	}
+
+	var pattern strings.Builder
+	pattern.WriteString(`(?s)^\s*`)
+	for i, elem := range elements {
+		pattern.WriteString(`(?:`)
+		pattern.WriteString(loosely(elem.BeforeString))
+		pattern.WriteString(`(?P<e` + strconv.Itoa(i) + `>` + valuePattern(elem) + `)`)
+		pattern.WriteString(loosely(elem.AfterString))
+		pattern.WriteString(`)`)
+		if elem.IsAllowEmpty() || mayBeMissing(elem) {
+			// prefer leaving optional elements out, so a free-text
+			// element doesn't swallow the structured ones after it
+			pattern.WriteString(`??`)
+		}
+	}
+	pattern.WriteString(`\s*$`)
+
+	re, err := regexp.Compile(pattern.String())
+	if err != nil {
+		return false
+	}
+	match := re.FindStringSubmatch(text)
+	if match == nil {
+		if elem, ok := freeTextElement(elements); ok {
+			values[elem.Name] = text
+		}
+		return false
+	}
+	for i, elem := range elements {
+		if value := strings.TrimSpace(match[re.SubexpIndex("e"+strconv.Itoa(i))]); value != "" {
+			values[elem.Name] = value
+		}
+	}
+	return true
+}
+
+// loosely returns a pattern matching s with any amount of whitespace
+// wherever s has whitespace, and around it
+func loosely(s string) string {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		if s == "" {
+			return ""
+		}
+		return `\s*`
+	}
+	for i, field := range fields {
+		fields[i] = regexp.QuoteMeta(field)
+	}
+	return `\s*` + strings.Join(fields, `\s*`) + `\s*`
+}
+
+// valuePattern returns the pattern for an element's value
+func valuePattern(elem config.Element) string {
+	switch config.GetEffectiveType(elem) {
+	case config.TypeSelect:
+		if !elem.IsModifiable() && len(elem.Options) > 0 {
+			return alternatives(elem.Options)
+		}
+	case config.TypeCoAuthors:
+		// one or more trailers, so free text before them can't run into them
+		return `(?i:co-authored-by:[^\n]*(?:\n\s*co-authored-by:[^\n]*)*)`
+	case config.TypeText:
+		switch elem.DataType {
+		case config.DataTypeInteger:
+			return `\d+`
+		case config.DataTypeFloat:
+			return `\d+\.\d+`
+		}
+	}
+	return `.+?`
+}
+
+// alternatives matches any of options, preferring the longest
+func alternatives(options []string) string {
+	sorted := append([]string(nil), options...)
+	sort.Slice(sorted, func(i, j int) bool { return len(sorted[i]) > len(sorted[j]) })
+	for i, option := range sorted {
+		sorted[i] = regexp.QuoteMeta(option)
+	}
+	return `(?:` + strings.Join(sorted, `|`) + `)`
+}
+
+// freeTextElement picks the element most likely to hold unstructured text:
+// the first multiline-text element, or else the last text element
+func freeTextElement(elements []config.Element) (config.Element, bool) {
+	for _, elem := range elements {
+		if config.GetEffectiveType(elem) == config.TypeMultilineText {
+			return elem, true
+		}
+	}
+	for i := len(elements) - 1; i >= 0; i-- {
+		if config.GetEffectiveType(elements[i]) == config.TypeText && elements[i].DataType == "" {
+			return elements[i], true
+		}
+	}
+	return config.Element{}, false
+}
@@ -0,0 +1,268 @@
+package message
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"git-com/config"
+)
+
+const testConfig = `type:
+  destination: title
+  type: select
+  options: [feat, fix, docs]
+scope:
+  destination: title
+  type: text
+  before-string: "("
+  after-string: ")"
+  allow-empty: true
+subject:
+  destination: title
+  type: text
+  before-string: ": "
+sure:
+  type: confirmation
+description:
+  destination: body
+  type: multiline-text
+  allow-empty: true
+ticket:
+  destination: body
+  data-type: integer
+  before-string: "\n\nTicket: "
+  allow-empty: true
+tags:
+  destination: body
+  type: multi-select
+  record-as: list
+  before-string: "\n\nTags:"
+  options: [hotfix, refactoring]
+  allow-empty: true
+`
+
+func loadTestConfig(t *testing.T) *config.Config {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), ".git-com.yaml")
+	if err := os.WriteFile(path, []byte(testConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.LoadConfigFromPath(path)
+	if err != nil {
+		t.Fatalf("LoadConfigFromPath() error = %v", err)
+	}
+	return cfg
+}
+
+func TestParse(t *testing.T) {
+	cfg := loadTestConfig(t)
+
+	tests := []struct {
+		name    string
+		message string
+		want    map[string]string
+		clean   bool
+	}{
+		{
+			name:    "everything",
+			message: "fix(parser): handle empty input\n\nIt used to crash.\n\nTicket: 42\n\nTags:\n- hotfix\n- refactoring\n",
+			want: map[string]string{
+				"type":        "fix",
+				"scope":       "parser",
+				"subject":     "handle empty input",
+				"description": "It used to crash.",
+				"ticket":      "42",
+				"tags":        "- hotfix\n- refactoring",
+			},
+			clean: true,
+		},
+		{
+			name:    "optional elements missing",
+			message: "feat: add a thing",
+			want: map[string]string{
+				"type":    "feat",
+				"subject": "add a thing",
+			},
+			clean: true,
+		},
+		{
+			name:    "loose whitespace",
+			message: "docs:   fix typo\n\nTicket:7",
+			want: map[string]string{
+				"type":    "docs",
+				"subject": "fix typo",
+				"ticket":  "7",
+			},
+			clean: true,
+		},
+		{
+			name:    "unstructured",
+			message: "Fixed some stuff\n\nLots of\nchanges",
+			want: map[string]string{
+				"subject":     "Fixed some stuff",
+				"description": "Lots of\nchanges",
+			},
+			clean: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Parse(cfg, tt.message); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %q, want %q", got, tt.want)
+			}
+			if _, clean := ParseChecked(cfg, tt.message); clean != tt.clean {
+				t.Errorf("ParseChecked() clean = %v, want %v", clean, tt.clean)
+			}
+		})
+	}
+}
+
+const templatedConfig = `title-template: '{{.commit_type}}{{if .scope}}({{.scope}}){{end}}: {{.subject}}'
+body-template: |
+  {{.description}}{{if .tags}}
+
+  Tags: {{join ", " .tags}}{{end}}
+commit-type:
+  destination: title
+  type: select
+  options: [feat, fix]
+scope:
+  destination: title
+  type: text
+  allow-empty: true
+subject:
+  destination: title
+  type: text
+description:
+  destination: body
+  type: multiline-text
+  allow-empty: true
+tags:
+  destination: body
+  type: multi-select
+  record-as: list
+  options: [hotfix, refactoring]
+  allow-empty: true
+`
+
+func TestParse_Templates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".git-com.yaml")
+	if err := os.WriteFile(path, []byte(templatedConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.LoadConfigFromPath(path)
+	if err != nil {
+		t.Fatalf("LoadConfigFromPath() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		message string
+		want    map[string]string
+		clean   bool
+	}{
+		{
+			name:    "everything",
+			message: "fix(parser): handle empty input\n\nIt used to crash.\n\nTags: hotfix, refactoring\n",
+			want: map[string]string{
+				"commit-type": "fix",
+				"scope":       "parser",
+				"subject":     "handle empty input",
+				"description": "It used to crash.",
+				"tags":        "- hotfix\n- refactoring",
+			},
+			clean: true,
+		},
+		{
+			name:    "optional elements missing",
+			message: "feat: add a thing",
+			want: map[string]string{
+				"commit-type": "feat",
+				"subject":     "add a thing",
+			},
+			clean: true,
+		},
+		{
+			name:    "not from the template",
+			message: "Fixed some stuff",
+			want:    map[string]string{"subject": "Fixed some stuff"},
+			clean:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, problems := Lint(cfg, tt.message)
+			if !reflect.DeepEqual(values, tt.want) {
+				t.Errorf("Lint() values = %q, want %q", values, tt.want)
+			}
+			if clean := len(problems) == 0; clean != tt.clean {
+				t.Errorf("Lint() problems = %q, want clean = %v", problems, tt.clean)
+			}
+		})
+	}
+}
+
+func TestLint_UnreadableTemplate(t *testing.T) {
+	cfg := loadTestConfig(t)
+	cfg.TitleTemplate = "{{upper .subject}}"
+
+	_, problems := Lint(cfg, "HANDLE EMPTY INPUT")
+	if !slices.ContainsFunc(problems, func(p string) bool { return strings.HasPrefix(p, "title-template can't be read back") }) {
+		t.Errorf("Lint() problems = %q, want the title-template reported as unreadable", problems)
+	}
+}
+
+func TestLint_ElementsThatMayBeMissing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".git-com.yaml")
+	source := `subject:
+  destination: title
+  type: text
+description:
+  destination: body
+  type: multiline-text
+branch:
+  destination: body
+  type: computed
+  command: git branch --show-current | grep -o '[A-Z]\+-[0-9]\+'
+  before-string: "\n\nRefs: "
+pair:
+  destination: body
+  type: co-authors
+  before-string: "\n\n"
+`
+	if err := os.WriteFile(path, []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.LoadConfigFromPath(path)
+	if err != nil {
+		t.Fatalf("LoadConfigFromPath() error = %v", err)
+	}
+
+	values, problems := Lint(cfg, "handle empty input\n\nIt used to crash.")
+	if len(problems) > 0 {
+		t.Errorf("Lint() problems = %q, want none for an empty computed value and no co-authors", problems)
+	}
+	want := map[string]string{"subject": "handle empty input", "description": "It used to crash."}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("Lint() values = %q, want %q", values, want)
+	}
+
+	values, problems = Lint(cfg, "handle empty input\n\nIt used to crash.\n\nRefs: ABC-1\n\nCo-authored-by: Dee <dee@example.com>")
+	if len(problems) > 0 {
+		t.Errorf("Lint() problems = %q, want none", problems)
+	}
+	if values["branch"] != "ABC-1" || values["pair"] != "Co-authored-by: Dee <dee@example.com>" {
+		t.Errorf("Lint() values = %q, want branch and pair filled in", values)
+	}
+
+	_, problems = Lint(cfg, "handle empty input")
+	if !slices.Contains(problems, "missing description") {
+		t.Errorf("Lint() problems = %q, want missing description", problems)
+	}
+}
@@ -0,0 +1,203 @@
+package message
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"git-com/config"
+)
+
+// errUnreadableTemplate is returned for templates that can't be reversed
+var errUnreadableTemplate = errors.New("can't be read back")
+
+// templateField is an element value written by a template
+type templateField struct {
+	elem config.Element
+	join string // the separator, when it's written with {{join}}
+	list bool   // true if it's written with {{join}}
+}
+
+// templateReader matches text rendered by a title-template or
+// body-template. Only what can be reversed is supported: {{.name}},
+// {{index . "name"}}, {{join "sep" .name}}, and {{if}} around them.
+type templateReader struct {
+	cfg     *config.Config
+	pattern strings.Builder
+	fields  []templateField
+}
+
+// newTemplateReader builds a reader for text. It returns
+// errUnreadableTemplate if text does anything else, like ranging over
+// a list or changing a value's case.
+func newTemplateReader(cfg *config.Config, name, text string) (*templateReader, *regexp.Regexp, error) {
+	tmpl, err := template.New(name).Funcs(config.TemplateFuncs).Parse(text)
+	if err != nil {
+		return nil, nil, err
+	}
+	r := &templateReader{cfg: cfg}
+	r.pattern.WriteString(`(?s)^\s*`)
+	if err := r.walk(tmpl.Root); err != nil {
+		return nil, nil, err
+	}
+	r.pattern.WriteString(`\s*$`)
+	re, err := regexp.Compile(r.pattern.String())
+	if err != nil {
+		return nil, nil, err
+	}
+	return r, re, nil
+}
+
+// walk adds the pattern for node
+func (r *templateReader) walk(node parse.Node) error {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, child := range n.Nodes {
+			if err := r.walk(child); err != nil {
+				return err
+			}
+		}
+	case *parse.TextNode:
+		r.pattern.WriteString(loosely(string(n.Text)))
+	case *parse.CommentNode:
+		// nothing is written
+	case *parse.ActionNode:
+		field, ok := r.field(n.Pipe)
+		if !ok {
+			return errUnreadableTemplate
+		}
+		valuePattern := valuePattern(field.elem)
+		if field.list {
+			valuePattern = `.+?`
+		}
+		r.pattern.WriteString(`(?P<f` + strconv.Itoa(len(r.fields)) + `>` + valuePattern + `)`)
+		if field.elem.IsAllowEmpty() || mayBeMissing(field.elem) {
+			r.pattern.WriteString(`??`)
+		}
+		r.fields = append(r.fields, field)
+	case *parse.IfNode:
+		// the condition isn't written, so it only has to be readable
+		if len(n.Pipe.Decl) > 0 {
+			return errUnreadableTemplate
+		}
+		r.pattern.WriteString(`(?:`)
+		if err := r.walk(n.List); err != nil {
+			return err
+		}
+		if n.ElseList != nil {
+			r.pattern.WriteString(`|`)
+			if err := r.walk(n.ElseList); err != nil {
+				return err
+			}
+			r.pattern.WriteString(`)`)
+		} else {
+			r.pattern.WriteString(`)?`)
+		}
+	default:
+		return errUnreadableTemplate
+	}
+	return nil
+}
+
+// field returns the element value pipe writes, if it only writes one
+func (r *templateReader) field(pipe *parse.PipeNode) (templateField, bool) {
+	if pipe == nil || len(pipe.Decl) > 0 || len(pipe.Cmds) != 1 {
+		return templateField{}, false
+	}
+	args := pipe.Cmds[0].Args
+	switch {
+	case len(args) == 1:
+		// {{.name}}
+		if f, ok := args[0].(*parse.FieldNode); ok && len(f.Ident) == 1 {
+			return r.lookup(f.Ident[0], "", false)
+		}
+	case len(args) == 3 && isIdentifier(args[0], "index"):
+		// {{index . "name"}}
+		_, dot := args[1].(*parse.DotNode)
+		if s, ok := args[2].(*parse.StringNode); ok && dot {
+			return r.lookup(s.Text, "", false)
+		}
+	case len(args) == 3 && isIdentifier(args[0], "join"):
+		// {{join ", " .name}}
+		sep, isString := args[1].(*parse.StringNode)
+		if f, ok := args[2].(*parse.FieldNode); ok && isString && len(f.Ident) == 1 {
+			return r.lookup(f.Ident[0], sep.Text, true)
+		}
+	}
+	return templateField{}, false
+}
+
+// lookup finds the element a template refers to as name. Multiple values
+// are only readable when they're joined, since otherwise they're written
+// the way Go prints a list.
+func (r *templateReader) lookup(name, join string, list bool) (templateField, bool) {
+	for _, elem := range r.cfg.Elements {
+		if elem.Name != name && strings.ReplaceAll(elem.Name, "-", "_") != name {
+			continue
+		}
+		if elem.IsMultiple() != list || (list && join == "") {
+			return templateField{}, false
+		}
+		return templateField{elem: elem, join: join, list: list}, true
+	}
+	return templateField{}, false
+}
+
+func isIdentifier(node parse.Node, name string) bool {
+	ident, ok := node.(*parse.IdentifierNode)
+	return ok && ident.Ident == name
+}
+
+// read records the values re found in text. It returns false if text
+// didn't match.
+func (r *templateReader) read(re *regexp.Regexp, text string, values map[string]string) bool {
+	match := re.FindStringSubmatch(text)
+	if match == nil {
+		return false
+	}
+	for i, field := range r.fields {
+		value := strings.TrimSpace(match[re.SubexpIndex("f"+strconv.Itoa(i))])
+		if value == "" || values[field.elem.Name] != "" {
+			continue
+		}
+		if field.list {
+			value = recorded(field.elem, strings.Split(value, field.join))
+		}
+		values[field.elem.Name] = value
+	}
+	return true
+}
+
+// names returns the names of the elements the template writes
+func (r *templateReader) names() []string {
+	names := make([]string, len(r.fields))
+	for i, field := range r.fields {
+		names[i] = field.elem.Name
+	}
+	return names
+}
+
+// recorded writes selections the way the element records them, so
+// values read from templates look like the ones read from joined
+// sections (see config.Element.Selections)
+func recorded(elem config.Element, selections []string) string {
+	var kept []string
+	for _, selection := range selections {
+		if selection = strings.TrimSpace(selection); selection != "" {
+			kept = append(kept, selection)
+		}
+	}
+	if elem.RecordAs == config.RecordAsList {
+		for i, selection := range kept {
+			kept[i] = elem.GetBulletString() + selection
+		}
+		return strings.Join(kept, "\n")
+	}
+	return strings.Join(kept, elem.GetJoinString())
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"strings"
+
+	"git-com/commit"
+	"git-com/output"
+	"git-com/prompt"
+	"git-com/tui"
+)
+
+// recentCommitLimit is how many commits the commit picker lists
+const recentCommitLimit = 30
+
+// revFlag is a flag with an optional revision, e.g. `--fixup` or `--fixup HEAD~2`.
+// It's a boolean flag as far as the flag package is concerned, so a
+// revision after a space is picked up by claimRevArgument.
+type revFlag struct {
+	set bool
+	rev string
+}
+
+func (f *revFlag) String() string   { return f.rev }
+func (f *revFlag) IsBoolFlag() bool { return true }
+func (f *revFlag) Set(value string) error {
+	f.set = true
+	if value != "true" {
+		f.rev = value
+	}
+	return nil
+}
+
+// claimRevArgument gives the first positional argument to a revFlag
+// that was used without a revision. It returns the remaining arguments.
+func claimRevArgument(args []string, flags ...*revFlag) []string {
+	for _, f := range flags {
+		if f.set && f.rev == "" && len(args) > 0 {
+			f.rev = args[0]
+			return args[1:]
+		}
+	}
+	return args
+}
+
+// verifySingleMode exits if more than one of --amend, --fixup, --squash,
+// and --reword was given
+func verifySingleMode(modes ...bool) {
+	count := 0
+	for _, mode := range modes {
+		if mode {
+			count++
+		}
+	}
+	if count > 1 {
+		output.PrintError("Only one of --amend, --fixup, --squash, and --reword can be used at a time.")
+		os.Exit(64)
+	}
+}
+
+// verifyCommitOptions exits if --author, --date, --reset-author, or
+// --allow-empty can't be used, so the user finds out before answering
+// any prompts. They only apply to the commits git com makes itself.
+func verifyCommitOptions(opts commit.Options, amend, reword, messageOnly bool) {
+	if given := commitOptionFlags(opts); len(given) > 0 {
+		if reword {
+			output.PrintError(strings.Join(given, ", ") + " can't be used with --reword, which keeps each commit's author and date.")
+			os.Exit(64)
+		}
+		if messageOnly {
+			output.PrintError(strings.Join(given, ", ") + " can't be used with --print or --output, which don't make a commit.")
+			os.Exit(64)
+		}
+	}
+	if opts.ResetAuthor && !amend {
+		output.PrintError("--reset-author can only be used with --amend.")
+		os.Exit(64)
+	}
+	if err := opts.Validate(); err != nil {
+		output.PrintError(err.Error())
+		os.Exit(64)
+	}
+}
+
+// commitOptionFlags returns the flags that set opts
+func commitOptionFlags(opts commit.Options) []string {
+	var given []string
+	if opts.Author != "" {
+		given = append(given, "--author")
+	}
+	if opts.Date != "" {
+		given = append(given, "--date")
+	}
+	if opts.ResetAuthor {
+		given = append(given, "--reset-author")
+	}
+	if opts.AllowEmpty {
+		given = append(given, "--allow-empty")
+	}
+	return given
+}
+
+// resolves the commit a revFlag refers to,
+// letting the user choose one when no revision was given.
+// prints an error and exits if there was a problem
+func chooseTargetCommit(f revFlag) commit.Summary {
+	if f.rev != "" {
+		target, err := commit.ResolveCommit(f.rev)
+		if err != nil {
+			output.PrintError(err.Error())
+			os.Exit(1)
+		}
+		return target
+	}
+
+	commits, err := commit.RecentCommits(recentCommitLimit)
+	if err != nil {
+		output.PrintError("Error listing commits: " + err.Error())
+		os.Exit(1)
+	}
+	labels := make([]string, len(commits))
+	for i, c := range commits {
+		labels[i] = c.String()
+	}
+
+	selected, err := tui.Choose(labels, 1, "Which commit?")
+	if err != nil {
+		if errors.Is(err, tui.ErrAborted) {
+			os.Exit(0)
+		}
+		output.PrintError("Error choosing commit: " + err.Error())
+		os.Exit(1)
+	}
+	for i, label := range labels {
+		if len(selected) > 0 && selected[0] == label {
+			return commits[i]
+		}
+	}
+	os.Exit(0)
+	return commit.Summary{}
+}
+
+// fixupResult is the message for `--fixup`, which autosquash uses
+// to fold this commit into target without changing its message
+func fixupResult(target commit.Summary) *prompt.Result {
+	return &prompt.Result{Title: "fixup! " + target.Subject}
+}
+
+// squashResult is the message for `--squash`. Autosquash combines the
+// entered message with target's when they're squashed together.
+func squashResult(target commit.Summary, entered *prompt.Result) *prompt.Result {
+	return &prompt.Result{
+		Title:     "squash! " + target.Subject,
+		Body:      commit.BuildCommitMessage(entered.Title, entered.Body),
+		CoAuthors: entered.CoAuthors,
+	}
+}
+
+// attempts to get the body of the commit being reworded
+// prints an error and exits if there was a problem
+func getCommitBody(target commit.Summary) *string {
+	body, err := commit.GetCommitBody(target.Hash)
+	if err != nil {
+		output.PrintError("Error getting commit body: " + err.Error())
+		os.Exit(1)
+	}
+	return body
+}
+
+// changes target's message and replays the commits after it
+// prints an error and exits if there was a problem
+func rewordCommit(target commit.Summary, result *prompt.Result) {
+	if err := commit.Reword(target.Hash, result.Title, result.Body); err != nil {
+		output.PrintError("Error rewording commit: " + err.Error())
+		os.Exit(1)
+	}
+}
@@ -31,3 +31,8 @@ func PrintWarningToStderr(msg string) {
 func Print(msg string) {
 	fmt.Println(msg)
 }
+
+// PrintToStderr prints a message to stderr without coloring
+func PrintToStderr(msg string) {
+	fmt.Fprintln(os.Stderr, msg)
+}
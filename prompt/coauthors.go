@@ -0,0 +1,121 @@
+package prompt
+
+import (
+	"strings"
+
+	"git-com/coauthors"
+	"git-com/config"
+	"git-com/output"
+	"git-com/tui"
+)
+
+// defaultNoCoAuthorsText labels the "skip" option of co-authors elements
+const defaultNoCoAuthorsText = "Just me"
+
+// HandleCoAuthors processes a co-authors element
+// defaultValue, a block of Co-authored-by trailers, pre-selects people
+func HandleCoAuthors(elem config.Element, defaultValue string) (string, error) {
+	people, err := coauthors.Candidates(elem.GetHistoryDepth())
+	if err != nil {
+		return "", err
+	}
+	// people from an old message may not be in recent history
+	for _, p := range coauthors.ParseTrailers(defaultValue) {
+		if !containsPerson(people, p) {
+			people = append(people, p)
+		}
+	}
+	if len(people) == 0 {
+		// nobody else has committed here yet
+		if elem.IsAllowEmpty() {
+			return "", nil
+		}
+		return askCoAuthor(elem)
+	}
+
+	options := make([]string, 0, len(people)+1)
+	emptyText := ""
+	if elem.IsAllowEmpty() {
+		emptyText = defaultNoCoAuthorsText
+		if elem.HasEmptySelectionText() {
+			emptyText = elem.EmptySelectionText
+		}
+		emptyText = Italicize(emptyText)
+		options = append(options, emptyText)
+	}
+	for _, p := range people {
+		options = append(options, p.String())
+	}
+
+	var selected []string
+	for _, p := range coauthors.ParseTrailers(defaultValue) {
+		selected = append(selected, p.String())
+	}
+
+	for {
+		selections, err := tui.ChooseWith(tui.ChooseOptions{
+			Options:      options,
+			Limit:        getMultiSelectLimit(elem),
+			Instructions: elem.Instructions,
+			Selected:     selected,
+		})
+		if err != nil {
+			if isAbortError(err) {
+				return "", ErrUserAborted
+			}
+			return "", err
+		}
+
+		if emptyText != "" && containsOption(selections, emptyText) {
+			return "", nil
+		}
+		if len(selections) == 0 && !elem.IsAllowEmpty() {
+			output.PrintWarningToStderr("This input is required.")
+			continue
+		}
+
+		var trailers []string
+		for _, selection := range selections {
+			if p, ok := coauthors.ParsePerson(selection); ok {
+				trailers = append(trailers, p.Trailer())
+			}
+		}
+		return strings.Join(trailers, "\n"), nil
+	}
+}
+
+// askCoAuthor asks for a co-author to be typed in, for required elements
+// when there's nobody to choose from
+func askCoAuthor(elem config.Element) (string, error) {
+	value, problem := "", ""
+	for {
+		result, err := tui.InputWith(tui.InputOptions{
+			Placeholder:  "Name <email>",
+			Instructions: "Nobody else has committed here yet. Who did you work with?",
+			Value:        value,
+			Error:        problem,
+		})
+		if err != nil {
+			if isAbortError(err) {
+				return "", ErrUserAborted
+			}
+			return "", err
+		}
+
+		p, ok := coauthors.ParsePerson(result)
+		if !ok {
+			value, problem = result, "Co-authors are written as Name <email>"
+			continue
+		}
+		return p.Trailer(), nil
+	}
+}
+
+func containsPerson(people []coauthors.Person, p coauthors.Person) bool {
+	for _, existing := range people {
+		if strings.EqualFold(existing.Email, p.Email) {
+			return true
+		}
+	}
+	return false
+}
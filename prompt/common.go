@@ -3,6 +3,7 @@ package prompt
 import (
 	"errors"
 	"fmt"
+	"os"
 	"strings"
 
 	"git-com/config"
@@ -28,9 +29,10 @@ func Italicize(text string) string {
 	return italicStyle.Render(text)
 }
 
-// ClearScreen clears the terminal screen
+// ClearScreen clears the terminal screen.
+// It writes to stderr, like the prompts, so stdout stays clean for --print.
 func ClearScreen() {
-	fmt.Print("\033[H\033[2J")
+	fmt.Fprint(os.Stderr, "\033[H\033[2J")
 }
 
 // isAbortError checks if the error is an abort error from tui
@@ -38,28 +40,43 @@ func isAbortError(err error) bool {
 	return errors.Is(err, tui.ErrAborted)
 }
 
-// handleOtherSelection handles when user selects "Other…" to add a new item
-func handleOtherSelection(elementName string, cfg *config.Config) (string, error) {
-	result, err := tui.Input("Enter new option…", "Add & select a new item")
-	if err != nil {
-		if isAbortError(err) {
+// handleOtherSelection handles when user selects "Other…" to add a new item.
+// The item has to pass the element's validate-command before it's saved.
+func handleOtherSelection(elem config.Element, cfg *config.Config) (string, error) {
+	value, problem := "", ""
+	for {
+		result, err := tui.InputWith(tui.InputOptions{
+			Placeholder:  "Enter new option…",
+			Instructions: "Add & select a new item",
+			Value:        value,
+			Error:        problem,
+		})
+		if err != nil {
+			if isAbortError(err) {
+				return "", ErrUserAborted
+			}
+			return "", err
+		}
+
+		newValue := strings.TrimSpace(result)
+		if newValue == "" {
 			return "", ErrUserAborted
 		}
-		return "", err
-	}
 
-	newValue := strings.TrimSpace(result)
-	if newValue == "" {
-		return "", ErrUserAborted
-	}
+		// Keep the rejected item so it can be fixed
+		if err := elem.CheckValue(newValue); err != nil {
+			value, problem = newValue, err.Error()
+			continue
+		}
 
-	// Save the new option to the config file
-	if cfg != nil {
-		if err := cfg.AddOptionToElement(elementName, newValue); err != nil {
-			// Log the error but don't fail - the value is still usable
-			output.PrintWarning("Could not save new option to config: " + err.Error())
+		// Save the new option to the config file
+		if cfg != nil {
+			if err := cfg.AddOptionToElement(elem.Name, newValue); err != nil {
+				// Log the error but don't fail - the value is still usable
+				output.PrintWarningToStderr("Could not save new option to config: " + err.Error())
+			}
 		}
-	}
 
-	return newValue, nil
+		return newValue, nil
+	}
 }
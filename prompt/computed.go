@@ -0,0 +1,25 @@
+package prompt
+
+import (
+	"fmt"
+
+	"git-com/config"
+)
+
+// HandleComputed works out a computed element's value without prompting.
+// values holds what the earlier elements were answered with. When
+// rewording, defaultValue is what the commit already has and is kept.
+func HandleComputed(elem config.Element, cfg *config.Config, values map[string]string, defaultValue string) (string, error) {
+	if defaultValue != "" {
+		return defaultValue, nil
+	}
+
+	value, err := cfg.ComputeValue(elem, values)
+	if err != nil {
+		return "", err
+	}
+	if value == "" && !elem.IsAllowEmpty() {
+		return "", fmt.Errorf("%s: computed value is empty (set allow-empty: true to allow this)", elem.Name)
+	}
+	return value, nil
+}
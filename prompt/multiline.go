@@ -20,9 +20,15 @@ func HandleMultilineText(elem config.Element, initialContent *string) (string, e
 		placeholder = WritingPrompt
 	}
 
+	problem := ""
 	for {
 		// Get multiline text input
-		result, err := tui.Write(placeholder, elem.Instructions, initialContent)
+		result, err := tui.WriteWith(tui.WriteOptions{
+			Placeholder:  placeholder,
+			Instructions: elem.Instructions,
+			Value:        initialContent,
+			Error:        problem,
+		})
 		if err != nil {
 			if isAbortError(err) {
 				return "", ErrUserAborted
@@ -32,13 +38,23 @@ func HandleMultilineText(elem config.Element, initialContent *string) (string, e
 
 		// Trim whitespace
 		result = strings.TrimSpace(result)
+		problem = ""
 
 		// Check if empty is allowed
 		if result == "" && !elem.IsAllowEmpty() {
-			output.PrintWarning("This input is required.")
+			output.PrintWarningToStderr("This input is required.")
 			continue
 		}
 
+		// Run validate-command, keeping the rejected text so it can be fixed
+		if result != "" {
+			if err := elem.CheckValue(result); err != nil {
+				problem = err.Error()
+				initialContent = &result
+				continue
+			}
+		}
+
 		// Clear initialContent after first use so it doesn't persist
 		// to the next invocation of HandleMultilineText
 		// Although, it'd be pretty silly to have 2 multiline-text
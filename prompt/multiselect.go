@@ -1,6 +1,7 @@
 package prompt
 
 import (
+	"slices"
 	"strings"
 
 	"git-com/config"
@@ -9,12 +10,20 @@ import (
 )
 
 // HandleMultiSelect processes a multi-select element
-func HandleMultiSelect(elem config.Element, cfg *config.Config) (string, error) {
+// defaultValue, in the element's recorded format, pre-selects options
+func HandleMultiSelect(elem config.Element, cfg *config.Config, defaultValue string) (string, error) {
 	options, emptyText := buildMultiSelectOptions(elem)
 	limit := getMultiSelectLimit(elem)
 
 	for {
-		selections, err := tui.Choose(options, limit, elem.Instructions)
+		selections, err := tui.ChooseWith(tui.ChooseOptions{
+			Options:      options,
+			Limit:        limit,
+			Instructions: elem.Instructions,
+			Selected:     parseMultiSelectResult(defaultValue, elem),
+
+			InSelectionOrder: elem.GetOrderBy() == config.OrderBySelection,
+		})
 		if err != nil {
 			if isAbortError(err) {
 				return "", ErrUserAborted
@@ -70,7 +79,7 @@ func processMultiSelectResult(selections []string, emptyText string, elem config
 
 	// Handle "Other…" selection
 	if elem.IsModifiable() && containsOption(selections, otherOption) {
-		newValue, err := handleOtherSelection(elem.Name, cfg)
+		newValue, err := handleOtherSelection(elem, cfg)
 		if err == ErrUserAborted {
 			return "", true, nil // Retry
 		}
@@ -82,13 +91,26 @@ func processMultiSelectResult(selections []string, emptyText string, elem config
 
 	// Check if empty is allowed
 	if len(selections) == 0 && !elem.IsAllowEmpty() {
-		output.PrintWarning("This input is required.")
+		output.PrintWarningToStderr("This input is required.")
 		return "", true, nil // Retry
 	}
 
+	if elem.GetOrderBy() == config.OrderByAlpha {
+		selections = sortAlphabetically(selections)
+	}
+
 	return formatMultiSelectResult(selections, elem), false, nil
 }
 
+// sortAlphabetically returns a sorted copy of selections, ignoring case
+func sortAlphabetically(selections []string) []string {
+	sorted := slices.Clone(selections)
+	slices.SortStableFunc(sorted, func(a, b string) int {
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	})
+	return sorted
+}
+
 // containsOption checks if a specific option is in the selections
 func containsOption(selections []string, option string) bool {
 	for _, sel := range selections {
@@ -134,6 +156,11 @@ func formatMultiSelectResult(selections []string, elem config.Element) string {
 	}
 }
 
+// parseMultiSelectResult reverses formatMultiSelectResult
+func parseMultiSelectResult(value string, elem config.Element) []string {
+	return elem.Selections(value)
+}
+
 // formatAsList formats selections as a bulleted list
 // Adds a leading newline (to separate from before-string) and a trailing newline
 func formatAsList(selections []string, bullet string) string {
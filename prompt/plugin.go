@@ -0,0 +1,57 @@
+package prompt
+
+import (
+	"errors"
+	"fmt"
+
+	"git-com/config"
+	"git-com/output"
+	"git-com/tui"
+)
+
+// HandlePlugin hands elem to the plugin at path (see config.RunPlugin).
+// The plugin either works out the value itself, running its own
+// interface if it needs one, or returns options that are offered here.
+// values holds what the earlier elements were answered with.
+func HandlePlugin(path string, elem config.Element, values map[string]string, defaultValue string) (string, error) {
+	response, err := config.RunPlugin(path, elem, defaultValue, values)
+	if errors.Is(err, config.ErrPluginAborted) {
+		return "", ErrUserAborted
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if response.Value != nil {
+		if *response.Value == "" && !elem.IsAllowEmpty() {
+			return "", fmt.Errorf("%s: the plugin returned an empty value", elem.Name)
+		}
+		return *response.Value, nil
+	}
+
+	limit := 1
+	if response.Multiple {
+		limit = getMultiSelectLimit(elem)
+	}
+	for {
+		selections, err := tui.ChooseWith(tui.ChooseOptions{
+			Options:      response.Options,
+			Limit:        limit,
+			Instructions: elem.Instructions,
+			Selected:     parseMultiSelectResult(defaultValue, elem),
+		})
+		if err != nil {
+			if isAbortError(err) {
+				return "", ErrUserAborted
+			}
+			return "", err
+		}
+
+		if len(selections) == 0 && !elem.IsAllowEmpty() {
+			output.PrintWarningToStderr("This input is required.")
+			continue
+		}
+
+		return formatMultiSelectResult(selections, elem), nil
+	}
+}
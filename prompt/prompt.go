@@ -1,6 +1,7 @@
 package prompt
 
 import (
+	"git-com/coauthors"
 	"git-com/config"
 )
 
@@ -8,26 +9,53 @@ import (
 type Result struct {
 	Title string
 	Body  string
+
+	// CoAuthors are the people chosen in co-authors elements. They're
+	// remembered (see coauthors.RememberPairing) once the commit is made.
+	CoAuthors []coauthors.Person
 }
 
 // ProcessElements processes all elements and builds the commit message
 // If oldCommitMessage is not nil, it will be used to pre-fill the first
 // multiline-text element with destination=body
 func ProcessElements(cfg *config.Config, oldCommitMessage *string) (*Result, error) {
+	return processElements(cfg, oldCommitMessage, nil)
+}
+
+// ProcessElementsWithDefaults processes all elements, pre-filling each
+// one with its value from defaults (keyed by element name) if present.
+// Multi-select values are in their recorded format.
+func ProcessElementsWithDefaults(cfg *config.Config, defaults map[string]string) (*Result, error) {
+	return processElements(cfg, nil, defaults)
+}
+
+func processElements(cfg *config.Config, oldCommitMessage *string, defaults map[string]string) (*Result, error) {
 	result := &Result{
 		Title: "",
 		Body:  "",
 	}
+	values := map[string]string{}
 
 	for _, elem := range cfg.Elements {
-		// Clear screen before each element
-		ClearScreen()
+		var value string
+		var err error
+		if config.GetEffectiveType(elem) == config.TypeComputed {
+			// nothing is shown, so leave the screen alone
+			value, err = HandleComputed(elem, cfg, values, defaults[elem.Name])
+		} else {
+			// Clear screen before each element
+			ClearScreen()
 
-		// Process element based on type
-		value, err := processElement(elem, cfg, &oldCommitMessage)
+			// Process element based on type
+			value, err = processElement(elem, cfg, &oldCommitMessage, defaults[elem.Name], values)
+		}
 		if err != nil {
 			return nil, err
 		}
+		values[elem.Name] = value
+		if config.GetEffectiveType(elem) == config.TypeCoAuthors {
+			result.CoAuthors = append(result.CoAuthors, coauthors.ParseTrailers(value)...)
+		}
 
 		// Skip if value is empty
 		if value == "" {
@@ -38,44 +66,84 @@ func ProcessElements(cfg *config.Config, oldCommitMessage *string) (*Result, err
 		finalValue := applyDecorators(value, elem)
 
 		// Append to appropriate destination
-		switch elem.Destination {
-		case config.DestTitle:
+		switch {
+		case config.GetEffectiveType(elem) == config.TypeCoAuthors:
+			// trailers need a paragraph of their own, and come last
+			// (see config.CheckConfig)
+			result.Body = coauthors.AppendTrailers(result.Body, finalValue)
+		case elem.Destination == config.DestTitle:
 			result.Title += finalValue
-		case config.DestBody:
+		case elem.Destination == config.DestBody:
 			result.Body += finalValue
 		}
 	}
 
+	// Templates replace the joined values for their destination
+	if err := renderTemplates(cfg, values, result); err != nil {
+		return nil, err
+	}
+
 	return result, nil
 }
 
+// renderTemplates renders title-template and body-template, if there are any
+func renderTemplates(cfg *config.Config, values map[string]string, result *Result) error {
+	for _, dest := range []config.Destination{config.DestTitle, config.DestBody} {
+		if cfg.Template(dest) == "" {
+			continue
+		}
+		rendered, err := cfg.RenderTemplate(dest, values)
+		if err != nil {
+			return err
+		}
+		if dest == config.DestTitle {
+			result.Title = rendered
+		} else {
+			result.Body = rendered
+		}
+	}
+	return nil
+}
+
 // processElement routes to the appropriate handler based on element type
 // oldCommitMessage is a pointer to a pointer so we can set it to nil after use
-func processElement(elem config.Element, cfg *config.Config, oldCommitMessage **string) (string, error) {
+// defaultValue pre-fills the element when it isn't empty
+// values holds the earlier elements' values, for plugins
+func processElement(elem config.Element, cfg *config.Config, oldCommitMessage **string, defaultValue string, values map[string]string) (string, error) {
 	// Get effective type (handles inference from data-type)
 	elemType := config.GetEffectiveType(elem)
 
 	switch elemType {
 	case config.TypeText:
-		return HandleText(elem)
+		return HandleText(elem, cfg, defaultValue)
 	case config.TypeMultilineText:
 		// Only pass oldCommitMessage if destination is body
 		var initialContent *string
-		if elem.Destination == config.DestBody && oldCommitMessage != nil && *oldCommitMessage != nil {
+		if defaultValue != "" {
+			initialContent = &defaultValue
+		} else if elem.Destination == config.DestBody && oldCommitMessage != nil && *oldCommitMessage != nil {
 			initialContent = *oldCommitMessage
 			// Set to nil after use so it's only used once
 			*oldCommitMessage = nil
 		}
 		return HandleMultilineText(elem, initialContent)
 	case config.TypeSelect:
-		return HandleSelect(elem, cfg)
+		return HandleSelect(elem, cfg, defaultValue)
 	case config.TypeMultiSelect:
-		return HandleMultiSelect(elem, cfg)
+		return HandleMultiSelect(elem, cfg, defaultValue)
 	case config.TypeConfirmation:
 		return HandleConfirmation(elem)
+	case config.TypeCoAuthors:
+		return HandleCoAuthors(elem, defaultValue)
+	case config.TypeTicket:
+		return HandleTicket(elem, cfg, defaultValue)
 	default:
+		// Other types are handled by plugins
+		if path, ok := config.FindPlugin(elemType); ok {
+			return HandlePlugin(path, elem, values, defaultValue)
+		}
 		// Fallback to text input
-		return HandleText(elem)
+		return HandleText(elem, cfg, defaultValue)
 	}
 }
 
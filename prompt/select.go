@@ -7,11 +7,17 @@ import (
 )
 
 // HandleSelect processes a select element
-func HandleSelect(elem config.Element, cfg *config.Config) (string, error) {
+// defaultValue starts out highlighted when it's one of the options
+func HandleSelect(elem config.Element, cfg *config.Config, defaultValue string) (string, error) {
 	options := buildSelectOptions(elem)
 
 	for {
-		selected, err := tui.Choose(options, 1, elem.Instructions)
+		selected, err := tui.ChooseWith(tui.ChooseOptions{
+			Options:      options,
+			Limit:        1,
+			Instructions: elem.Instructions,
+			Selected:     []string{defaultValue},
+		})
 		if err != nil {
 			if isAbortError(err) {
 				return "", ErrUserAborted
@@ -53,7 +59,7 @@ func processSelectResult(selected []string, elem config.Element, cfg *config.Con
 
 	// Handle "Other…" selection
 	if result == otherOption {
-		newValue, err := handleOtherSelection(elem.Name, cfg)
+		newValue, err := handleOtherSelection(elem, cfg)
 		if err == ErrUserAborted {
 			return "", true, nil // Retry
 		}
@@ -65,7 +71,7 @@ func processSelectResult(selected []string, elem config.Element, cfg *config.Con
 
 	// Check if empty is allowed
 	if result == "" && !elem.IsAllowEmpty() {
-		output.PrintWarning("This input is required.")
+		output.PrintWarningToStderr("This input is required.")
 		return "", true, nil // Retry
 	}
 
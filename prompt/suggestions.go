@@ -0,0 +1,37 @@
+package prompt
+
+import (
+	"git-com/coauthors"
+	"git-com/commit"
+	"git-com/config"
+	"git-com/history"
+)
+
+// recentCommits are the commits suggestions come from, keyed by how many
+// were asked for, so they're only read once however many elements use them
+var recentCommits = map[int][]commit.Summary{}
+
+// textSuggestions returns the values elem had in recent commits, to be
+// offered as completions. Nothing is suggested when its history is off.
+func textSuggestions(elem config.Element, cfg *config.Config) []string {
+	source := elem.GetHistory()
+	if source == config.HistoryOff || cfg == nil {
+		return nil
+	}
+
+	email := ""
+	if source == config.HistoryMine {
+		if email = coauthors.CurrentUser().Email; email == "" {
+			return nil
+		}
+	}
+
+	depth := elem.GetHistoryDepth()
+	commits, ok := recentCommits[depth]
+	if !ok {
+		// without any history there's simply nothing to suggest
+		commits, _ = commit.RecentCommits(depth)
+		recentCommits[depth] = commits
+	}
+	return history.Suggestions(cfg, elem, commits, email)
+}
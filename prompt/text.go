@@ -15,10 +15,20 @@ var (
 )
 
 // HandleText processes a text input element
-func HandleText(elem config.Element) (string, error) {
+// defaultValue pre-fills the input when it isn't empty
+// Values from earlier commits are offered as suggestions (see textSuggestions)
+func HandleText(elem config.Element, cfg *config.Config, defaultValue string) (string, error) {
+	suggestions := textSuggestions(elem, cfg)
+	problem := ""
 	for {
 		// Get text input
-		result, err := tui.Input(elem.Placeholder, elem.Instructions)
+		result, err := tui.InputWith(tui.InputOptions{
+			Placeholder:  elem.Placeholder,
+			Instructions: elem.Instructions,
+			Value:        defaultValue,
+			Error:        problem,
+			Suggestions:  suggestions,
+		})
 		if err != nil {
 			if isAbortError(err) {
 				return "", ErrUserAborted
@@ -28,6 +38,7 @@ func HandleText(elem config.Element) (string, error) {
 
 		// Trim whitespace
 		result = strings.TrimSpace(result)
+		problem = ""
 
 		// Validate data type if specified
 		if elem.DataType != "" && result != "" {
@@ -39,10 +50,19 @@ func HandleText(elem config.Element) (string, error) {
 
 		// Check if empty is allowed
 		if result == "" && !elem.IsAllowEmpty() {
-			output.PrintWarning("This input is required.")
+			output.PrintWarningToStderr("This input is required.")
 			continue
 		}
 
+		// Run validate-command, keeping the rejected value so it can be fixed
+		if result != "" {
+			if err := elem.CheckValue(result); err != nil {
+				problem = err.Error()
+				defaultValue = result
+				continue
+			}
+		}
+
 		return result, nil
 	}
 }
@@ -0,0 +1,125 @@
+package prompt
+
+import (
+	"fmt"
+	"strings"
+
+	"git-com/config"
+	"git-com/output"
+	"git-com/tui"
+)
+
+// HandleTicket processes a ticket element: a searchable list of the
+// tickets from its ticket-file or ticket-command. Tickets that aren't
+// listed can be typed in if they match the element's pattern. With
+// multiple: true a typed in ticket is added to the list, selected, and
+// the list is shown again so more can be chosen.
+// defaultValue, in the recorded format, pre-selects tickets.
+func HandleTicket(elem config.Element, cfg *config.Config, defaultValue string) (string, error) {
+	tickets, err := cfg.LoadTickets(elem)
+	if err != nil {
+		return "", err
+	}
+	if len(tickets) == 0 && elem.Pattern == "" {
+		return "", fmt.Errorf("%s: no tickets found", elem.Name)
+	}
+
+	limit := 1
+	if elem.IsMultiple() {
+		limit = getMultiSelectLimit(elem)
+	}
+
+	_, byLabel := buildTicketOptions(elem, tickets)
+	var selected []string
+	for _, value := range elem.Selections(defaultValue) {
+		found := false
+		for label, ticket := range byLabel {
+			if elem.TicketValue(ticket) == value {
+				selected = append(selected, label)
+				found = true
+			}
+		}
+		if !found && elem.MatchesPattern(value) {
+			// it was typed in last time
+			tickets = append(tickets, config.Ticket{ID: value})
+			selected = append(selected, value)
+		}
+	}
+
+	query, problem := "", ""
+	for {
+		options, byLabel := buildTicketOptions(elem, tickets)
+		result, err := tui.Filter(tui.FilterOptions{
+			Options:      options,
+			Limit:        limit,
+			Instructions: elem.Instructions,
+			Placeholder:  "Type to search…",
+			Selected:     selected,
+			Value:        query,
+			Error:        problem,
+			AllowNew:     elem.Pattern != "",
+		})
+		if err != nil {
+			if isAbortError(err) {
+				return "", ErrUserAborted
+			}
+			return "", err
+		}
+		query, problem = "", ""
+		selected = result.Selected
+
+		// Something that isn't listed was typed in
+		if result.Query != "" {
+			if !elem.MatchesPattern(result.Query) {
+				query = result.Query
+				problem = fmt.Sprintf("%s isn't listed, and doesn't look like a ticket (%s)", result.Query, elem.Pattern)
+				continue
+			}
+			if !elem.IsMultiple() {
+				return result.Query, nil
+			}
+			if limit > 0 && len(result.Selected) >= limit {
+				query = result.Query
+				problem = fmt.Sprintf("Only %d tickets can be chosen", limit)
+				continue
+			}
+			typed := config.Ticket{ID: result.Query}
+			tickets = append(tickets, typed)
+			selected = append(selected, typed.Label())
+			continue
+		}
+
+		var values []string
+		for _, label := range result.Selected {
+			if ticket, ok := byLabel[label]; ok {
+				values = append(values, elem.TicketValue(ticket))
+			}
+		}
+
+		// Check if empty is allowed
+		if len(values) == 0 && !elem.IsAllowEmpty() {
+			output.PrintWarningToStderr("This input is required.")
+			continue
+		}
+
+		return strings.Join(values, elem.GetJoinString()), nil
+	}
+}
+
+// buildTicketOptions labels each ticket for the picker, with the option
+// to skip first when the element may be left empty
+func buildTicketOptions(elem config.Element, tickets []config.Ticket) (options []string, byLabel map[string]config.Ticket) {
+	byLabel = make(map[string]config.Ticket, len(tickets))
+	if elem.IsAllowEmpty() {
+		options = append(options, Italicize(elem.GetEmptySelectionText()))
+	}
+	for _, ticket := range tickets {
+		label := ticket.Label()
+		if _, duplicate := byLabel[label]; duplicate {
+			continue
+		}
+		byLabel[label] = ticket
+		options = append(options, label)
+	}
+	return options, byLabel
+}
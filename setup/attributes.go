@@ -0,0 +1,358 @@
+package setup
+
+import (
+	"strconv"
+	"strings"
+
+	"git-com/config"
+	"git-com/output"
+	"git-com/tui"
+)
+
+// elementTypes returns the element types offered when adding an element
+func elementTypes() []string {
+	return []string{
+		string(config.TypeText),
+		string(config.TypeMultilineText),
+		string(config.TypeSelect),
+		string(config.TypeMultiSelect),
+		string(config.TypeConfirmation),
+		string(config.TypeCoAuthors),
+		string(config.TypeComputed),
+		string(config.TypeTicket),
+	}
+}
+
+// editAttributes prompts for every attribute that applies to elem's type.
+// Current text values are filled in, so they can be kept or cleared;
+// for numbers, submitting an empty answer keeps them.
+func editAttributes(elem *config.Element) error {
+	elemType := config.GetEffectiveType(*elem)
+
+	if elemType == config.TypeConfirmation {
+		// confirmations only ever need a question
+		elem.Destination = ""
+		return askString(&elem.Instructions, "Are you sure?", "What question should be asked?")
+	}
+
+	if elemType == config.TypeCoAuthors {
+		// trailers have to be at the end of the body
+		elem.Destination = config.DestBody
+	} else if err := askDestination(elem); err != nil {
+		return err
+	}
+	if elemType != config.TypeComputed {
+		// computed elements are never shown
+		if err := askString(&elem.Instructions, "(none)", "Instructions shown above the prompt"); err != nil {
+			return err
+		}
+	}
+	if err := askEscapedString(&elem.BeforeString, "Text added before the value (\\n for a newline)"); err != nil {
+		return err
+	}
+	if err := askEscapedString(&elem.AfterString, "Text added after the value (\\n for a newline)"); err != nil {
+		return err
+	}
+	if err := askBool(&elem.AllowEmpty, "May this element be left empty?"); err != nil {
+		return err
+	}
+
+	switch elemType {
+	case config.TypeText:
+		return editTextAttributes(elem)
+	case config.TypeMultilineText:
+		if err := askString(&elem.Placeholder, "Write something…", "Placeholder text"); err != nil {
+			return err
+		}
+		return askValidateCommand(elem, "Command that checks the text (it's given on stdin, a non-zero exit rejects it)")
+	case config.TypeSelect:
+		return editSelectAttributes(elem)
+	case config.TypeMultiSelect:
+		return editMultiSelectAttributes(elem)
+	case config.TypeCoAuthors:
+		return editCoAuthorsAttributes(elem)
+	case config.TypeComputed:
+		return editComputedAttributes(elem)
+	case config.TypeTicket:
+		return editTicketAttributes(elem)
+	}
+	return nil
+}
+
+// editTextAttributes prompts for text specific attributes
+func editTextAttributes(elem *config.Element) error {
+	if err := askString(&elem.Placeholder, "(none)", "Placeholder text"); err != nil {
+		return err
+	}
+	dataType, err := chooseOne(
+		[]string{string(config.DataTypeString), string(config.DataTypeInteger), string(config.DataTypeFloat)},
+		"What kind of data is allowed?",
+	)
+	if err != nil {
+		return err
+	}
+	elem.DataType = config.DataType(dataType)
+	if elem.DataType == config.DataTypeString {
+		// string is the default, no need to write it out
+		elem.DataType = ""
+	}
+	history, err := chooseOne(
+		[]string{string(config.HistoryMine), string(config.HistoryAll), string(config.HistoryOff)},
+		"Suggest values from whose earlier commits? (off for anything sensitive)",
+	)
+	if err != nil {
+		return err
+	}
+	elem.History = config.HistorySource(history)
+	if elem.History == config.HistoryMine {
+		// mine is the default, no need to write it out
+		elem.History = ""
+	}
+	return askValidateCommand(elem, "Command that checks the value (it's given on stdin, a non-zero exit rejects it)")
+}
+
+// askValidateCommand prompts for the element's validate-command
+func askValidateCommand(elem *config.Element, instructions string) error {
+	return askString(&elem.ValidateCommand, "(none)", instructions)
+}
+
+// editSelectAttributes prompts for select specific attributes
+func editSelectAttributes(elem *config.Element) error {
+	if err := askOptions(elem); err != nil {
+		return err
+	}
+	if err := askBool(&elem.Modifiable, "Allow adding new options with \"Other…\"?"); err != nil {
+		return err
+	}
+	if !elem.IsModifiable() {
+		return nil
+	}
+	return askValidateCommand(elem, "Command that checks options added with \"Other…\" (given on stdin, a non-zero exit rejects it)")
+}
+
+// editMultiSelectAttributes prompts for multi-select specific attributes
+func editMultiSelectAttributes(elem *config.Element) error {
+	if err := editSelectAttributes(elem); err != nil {
+		return err
+	}
+
+	recordAs := []string{string(config.RecordAsJoinedString), string(config.RecordAsList)}
+	if elem.Destination == config.DestTitle {
+		// lists contain newlines which aren't allowed in titles
+		recordAs = recordAs[:1]
+	}
+	choice, err := chooseOne(recordAs, "How should the selections be recorded?")
+	if err != nil {
+		return err
+	}
+	elem.RecordAs = config.RecordAs(choice)
+
+	if elem.RecordAs == config.RecordAsList {
+		if err := askString(&elem.BulletString, config.BulletListPrefix, "Bullet string"); err != nil {
+			return err
+		}
+	} else {
+		if err := askString(&elem.JoinString, config.JoinSeparator, "Join string"); err != nil {
+			return err
+		}
+	}
+
+	orderBy, err := chooseOne(
+		[]string{string(config.OrderByOptions), string(config.OrderBySelection), string(config.OrderByAlpha)},
+		"What order should the selections be recorded in?",
+	)
+	if err != nil {
+		return err
+	}
+	elem.OrderBy = config.OrderBy(orderBy)
+	if elem.OrderBy == config.OrderByOptions {
+		// options is the default, no need to write it out
+		elem.OrderBy = ""
+	}
+
+	if elem.IsAllowEmpty() {
+		if err := askString(&elem.EmptySelectionText, "No Selection", "Label for the \"skip\" option"); err != nil {
+			return err
+		}
+	} else {
+		elem.EmptySelectionText = ""
+	}
+
+	return askLimit(elem)
+}
+
+// editCoAuthorsAttributes prompts for co-authors specific attributes
+func editCoAuthorsAttributes(elem *config.Element) error {
+	if err := askInt(&elem.HistoryDepth, strconv.Itoa(config.DefaultHistoryDepth), "How many recent commits should co-authors be found in?"); err != nil {
+		return err
+	}
+	return askLimit(elem)
+}
+
+// editComputedAttributes prompts for where a computed element's value comes from
+func editComputedAttributes(elem *config.Element) error {
+	source, err := chooseOne([]string{"template", "command"}, "Where does the value come from?")
+	if err != nil {
+		return err
+	}
+	if source == "template" {
+		elem.Command = ""
+		return askString(&elem.Template, "{{branch}}", "Template")
+	}
+	elem.Template = ""
+	return askString(&elem.Command, "git rev-parse --abbrev-ref HEAD", "Shell command")
+}
+
+// editTicketAttributes prompts for ticket specific attributes
+func editTicketAttributes(elem *config.Element) error {
+	source, err := chooseOne([]string{"file", "command"}, "Where does the list of tickets come from?")
+	if err != nil {
+		return err
+	}
+	if source == "file" {
+		elem.TicketCommand = ""
+		err = askString(&elem.TicketFile, "tickets.csv", "JSON or CSV file of ticket ids and titles")
+	} else {
+		elem.TicketFile = ""
+		err = askString(&elem.TicketCommand, "sqlite3 -csv issues.db 'select id, title from issues'", "Command that prints ticket ids and titles")
+	}
+	if err != nil {
+		return err
+	}
+	if err := askBool(&elem.RecordTitle, "Record each ticket's title after its id?"); err != nil {
+		return err
+	}
+	if err := askString(&elem.Pattern, "(none)", "Pattern tickets that aren't listed must match, like [A-Z]+-[0-9]+"); err != nil {
+		return err
+	}
+	if err := askBool(&elem.Multiple, "Allow choosing more than one ticket?"); err != nil {
+		return err
+	}
+	if elem.IsMultiple() {
+		return askLimit(elem)
+	}
+	return nil
+}
+
+// askDestination asks whether the element belongs in the title or body
+func askDestination(elem *config.Element) error {
+	choice, err := chooseOne(
+		[]string{string(config.DestTitle), string(config.DestBody)},
+		"Where should \""+elem.Name+"\" go in the commit message?",
+	)
+	if err != nil {
+		return err
+	}
+	elem.Destination = config.Destination(choice)
+	return nil
+}
+
+// askOptions collects the list of options, one per line
+func askOptions(elem *config.Element) error {
+	for {
+		var initial *string
+		if len(elem.Options) > 0 {
+			joined := strings.Join(elem.Options, "\n")
+			initial = &joined
+		}
+		result, err := tui.Write("one option per line", "What options should be offered?", initial)
+		if err != nil {
+			return err
+		}
+
+		var options []string
+		for _, line := range strings.Split(result, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				options = append(options, line)
+			}
+		}
+		if len(options) == 0 {
+			output.PrintWarningToStderr("This input is required.")
+			continue
+		}
+		elem.Options = options
+		return nil
+	}
+}
+
+// askLimit asks for the maximum number of selections
+func askLimit(elem *config.Element) error {
+	return askInt(&elem.Limit, "0 (unlimited)", "Maximum number of selections")
+}
+
+// askInt prompts for a non-negative integer.
+// An empty answer leaves the current value untouched.
+func askInt(target *int, placeholder, instructions string) error {
+	for {
+		result, err := tui.Input(placeholder, instructions)
+		if err != nil {
+			return err
+		}
+		result = strings.TrimSpace(result)
+		if result == "" {
+			return nil
+		}
+		value, err := strconv.Atoi(result)
+		if err != nil || value < 0 {
+			output.PrintError("Your input must be a integer")
+			continue
+		}
+		*target = value
+		return nil
+	}
+}
+
+// askString prompts for a single line string, pre-filled with the
+// current value. Clearing it removes the attribute.
+func askString(target *string, placeholder, instructions string) error {
+	result, err := tui.InputWith(stringInput(*target, placeholder, instructions, false))
+	if err != nil {
+		return err
+	}
+	*target = stringAnswer(result, false)
+	return nil
+}
+
+// askEscapedString is like askString but preserves surrounding whitespace
+// and turns a typed \n into a real newline.
+func askEscapedString(target *string, instructions string) error {
+	result, err := tui.InputWith(stringInput(*target, "(none)", instructions, true))
+	if err != nil {
+		return err
+	}
+	*target = stringAnswer(result, true)
+	return nil
+}
+
+// stringInput asks for a string attribute with current filled in, so
+// submitting it as it is keeps it and an empty answer removes it.
+// Escaped strings show newlines as \n.
+func stringInput(current, placeholder, instructions string, escaped bool) tui.InputOptions {
+	if escaped {
+		current = strings.ReplaceAll(current, "\n", `\n`)
+	}
+	return tui.InputOptions{Placeholder: placeholder, Instructions: instructions, Value: current}
+}
+
+// stringAnswer turns what was submitted for a string attribute back into its value
+func stringAnswer(result string, escaped bool) string {
+	if escaped {
+		return strings.ReplaceAll(result, `\n`, "\n")
+	}
+	return strings.TrimSpace(result)
+}
+
+// askBool asks a yes/no question and records the answer.
+// false is stored as nil because it's the default.
+func askBool(target **bool, question string) error {
+	confirmed, err := tui.Confirm(question)
+	if err != nil {
+		return err
+	}
+	if confirmed {
+		*target = boolPtr(true)
+	} else {
+		*target = nil
+	}
+	return nil
+}
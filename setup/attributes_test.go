@@ -0,0 +1,33 @@
+package setup
+
+import "testing"
+
+func TestStringAnswers(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  string
+		escaped  bool
+		answer   func(prefilled string) string
+		expected string
+	}{
+		{"kept", "Refs: ", true, func(v string) string { return v }, "Refs: "},
+		{"newlines kept", "\n\nRefs: ", true, func(v string) string { return v }, "\n\nRefs: "},
+		{"cleared", "\n\n", true, func(string) string { return "" }, ""},
+		{"changed", "\n\n", true, func(v string) string { return v + "Refs: " }, "\n\nRefs: "},
+		{"plain kept", "Write something", false, func(v string) string { return v }, "Write something"},
+		{"plain cleared", "Write something", false, func(string) string { return "" }, ""},
+		{"plain trimmed", "", false, func(string) string { return "  grep -q .  " }, "grep -q ."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := stringInput(tt.current, "(none)", "instructions", tt.escaped)
+			if got := stringAnswer(tt.answer(opts.Value), tt.escaped); got != tt.expected {
+				t.Errorf("answer for %q = %q, want %q", tt.current, got, tt.expected)
+			}
+		})
+	}
+
+	if opts := stringInput("\n\nRefs: ", "(none)", "", true); opts.Value != `\n\nRefs: ` {
+		t.Errorf("escaped value is filled in as %q, want newlines written as \\n", opts.Value)
+	}
+}
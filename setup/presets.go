@@ -0,0 +1,143 @@
+package setup
+
+import (
+	"git-com/config"
+)
+
+// preset is a named starting point for a new configuration
+type preset struct {
+	Name     string
+	Elements func() []config.Element
+}
+
+// presets are offered, in this order, when git com init starts
+var presets = []preset{
+	{Name: "Conventional Commits", Elements: conventionalCommitsElements},
+	{Name: "Bracketed section & change type", Elements: bracketedElements},
+	{Name: "Minimal (title & description)", Elements: minimalElements},
+	{Name: "Start from scratch", Elements: func() []config.Element { return nil }},
+}
+
+// presetNames returns the names of all presets in display order
+func presetNames() []string {
+	names := make([]string, len(presets))
+	for i, p := range presets {
+		names[i] = p.Name
+	}
+	return names
+}
+
+// findPreset returns the preset with the given name, or nil
+func findPreset(name string) *preset {
+	for i := range presets {
+		if presets[i].Name == name {
+			return &presets[i]
+		}
+	}
+	return nil
+}
+
+// conventionalCommitsElements mirrors the Quick Start in config_file_details.org
+func conventionalCommitsElements() []config.Element {
+	return []config.Element{
+		{
+			Name:         "commit-type",
+			Type:         config.TypeSelect,
+			Destination:  config.DestTitle,
+			Instructions: "What type of work?",
+			Options: []string{
+				"fix", "feat", "build", "chore", "ci", "docs",
+				"style", "refactor", "perf", "test", "fix!", "feat!",
+			},
+			BreakingOptions: []string{"fix!", "feat!"},
+			VersionBump:     map[string]string{"feat": config.BumpMinor, "fix": config.BumpPatch},
+		},
+		{
+			Name:         "scope",
+			Type:         config.TypeSelect,
+			Destination:  config.DestTitle,
+			Instructions: "(optional) What section of codebase?",
+			AllowEmpty:   boolPtr(true),
+			BeforeString: "(",
+			AfterString:  ")",
+			Modifiable:   boolPtr(true),
+			Options:      []string{"core", "docs", "tests"},
+		},
+		{
+			Name:         "subject",
+			Type:         config.TypeText,
+			Destination:  config.DestTitle,
+			BeforeString: ": ",
+			Placeholder:  "a short description…",
+		},
+		{
+			Name:         "commit-description",
+			Type:         config.TypeMultilineText,
+			Destination:  config.DestBody,
+			Instructions: "Please describe your changes.",
+			Placeholder:  "(optional details)",
+			AllowEmpty:   boolPtr(true),
+		},
+	}
+}
+
+// bracketedElements mirrors the .git-com.yaml used by git-com itself
+func bracketedElements() []config.Element {
+	return []config.Element{
+		{
+			Name:         "change-type",
+			Type:         config.TypeSelect,
+			Destination:  config.DestTitle,
+			Instructions: "What kind of change?",
+			BeforeString: "[",
+			AfterString:  "] ",
+			Modifiable:   boolPtr(true),
+			Options:      []string{"fix", "add", "refactor", "clean-up"},
+		},
+		{
+			Name:        "commit-title",
+			Type:        config.TypeText,
+			Destination: config.DestTitle,
+			Placeholder: "Commit Title",
+		},
+		{
+			Name:         "commit-description",
+			Type:         config.TypeMultilineText,
+			Destination:  config.DestBody,
+			Instructions: "Please describe your changes.",
+			AllowEmpty:   boolPtr(true),
+		},
+		{
+			Name:         "ticket-number",
+			Type:         config.TypeText,
+			Destination:  config.DestBody,
+			DataType:     config.DataTypeInteger,
+			Instructions: "Associated Ticket Number (if any)",
+			AllowEmpty:   boolPtr(true),
+			BeforeString: "\n\nTicket: ",
+		},
+	}
+}
+
+// minimalElements is the smallest useful configuration
+func minimalElements() []config.Element {
+	return []config.Element{
+		{
+			Name:        "commit-title",
+			Type:        config.TypeText,
+			Destination: config.DestTitle,
+			Placeholder: "Commit Title",
+		},
+		{
+			Name:         "commit-description",
+			Type:         config.TypeMultilineText,
+			Destination:  config.DestBody,
+			Instructions: "Please describe your changes.",
+			AllowEmpty:   boolPtr(true),
+		},
+	}
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
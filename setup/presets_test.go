@@ -0,0 +1,32 @@
+package setup
+
+import (
+	"testing"
+
+	"git-com/config"
+)
+
+func TestPresetsAreValid(t *testing.T) {
+	for _, p := range presets {
+		elements := p.Elements()
+		if len(elements) == 0 {
+			// "Start from scratch"
+			continue
+		}
+		t.Run(p.Name, func(t *testing.T) {
+			cfg := &config.Config{Elements: elements}
+			if !config.ValidateConfig(cfg) {
+				t.Errorf("preset %q does not pass ValidateConfig", p.Name)
+			}
+		})
+	}
+}
+
+func TestFindPreset(t *testing.T) {
+	if findPreset("Conventional Commits") == nil {
+		t.Error("expected to find the Conventional Commits preset")
+	}
+	if findPreset("nonexistent") != nil {
+		t.Error("expected nil for an unknown preset")
+	}
+}
@@ -0,0 +1,263 @@
+// Package setup implements `git com init`, an interactive generator
+// for .git-com.yaml files built on the same tui widgets as the commit flow.
+package setup
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"git-com/config"
+	"git-com/output"
+	"git-com/prompt"
+	"git-com/tui"
+)
+
+// menu actions
+const (
+	actionAdd    = "Add an element"
+	actionEdit   = "Edit an element"
+	actionMove   = "Move an element"
+	actionRemove = "Remove an element"
+	actionSave   = "Save and finish"
+)
+
+// Run walks the user through building a configuration and writes it to path.
+// Returns tui.ErrAborted if the user bails out before saving.
+func Run(path string) (*config.Config, error) {
+	cfg := &config.Config{FilePath: path}
+
+	elements, err := choosePreset()
+	if err != nil {
+		return nil, err
+	}
+	cfg.Elements = elements
+
+	for {
+		prompt.ClearScreen()
+		action, err := chooseOne(menuActions(cfg), describeElements(cfg))
+		if err != nil {
+			return nil, err
+		}
+
+		switch action {
+		case actionAdd:
+			err = addElement(cfg)
+		case actionEdit:
+			err = editElement(cfg)
+		case actionMove:
+			err = moveElement(cfg)
+		case actionRemove:
+			err = removeElement(cfg)
+		case actionSave:
+			if saved, saveErr := save(cfg); saveErr != nil || saved {
+				return cfg, saveErr
+			}
+		}
+		// aborting a sub-step just returns to the menu
+		if err != nil && !errors.Is(err, tui.ErrAborted) {
+			return nil, err
+		}
+	}
+}
+
+// choosePreset asks which preset to start from and returns its elements
+func choosePreset() ([]config.Element, error) {
+	name, err := chooseOne(presetNames(), "Which configuration would you like to start from?")
+	if err != nil {
+		return nil, err
+	}
+	p := findPreset(name)
+	if p == nil {
+		return nil, fmt.Errorf("unknown preset: %s", name)
+	}
+	return p.Elements(), nil
+}
+
+// menuActions returns the actions that make sense for the current config
+func menuActions(cfg *config.Config) []string {
+	actions := []string{actionAdd}
+	if len(cfg.Elements) > 0 {
+		actions = append(actions, actionEdit)
+	}
+	if len(cfg.Elements) > 1 {
+		actions = append(actions, actionMove)
+	}
+	if len(cfg.Elements) > 0 {
+		actions = append(actions, actionRemove)
+	}
+	return append(actions, actionSave)
+}
+
+// describeElements summarizes the elements configured so far
+func describeElements(cfg *config.Config) string {
+	if len(cfg.Elements) == 0 {
+		return "No elements yet. What would you like to do?"
+	}
+	var s strings.Builder
+	s.WriteString("Elements (in prompt order):\n")
+	for i, elem := range cfg.Elements {
+		dest := string(elem.Destination)
+		if dest == "" {
+			dest = "-"
+		}
+		fmt.Fprintf(&s, "  %d. %s (%s → %s)\n", i+1, elem.Name, config.GetEffectiveType(elem), dest)
+	}
+	s.WriteString("\nWhat would you like to do?")
+	return s.String()
+}
+
+// addElement prompts for a new element and appends it
+func addElement(cfg *config.Config) error {
+	name, err := askElementName(cfg, "")
+	if err != nil {
+		return err
+	}
+
+	elemType, err := chooseOne(elementTypes(), "What type of element is \""+name+"\"?")
+	if err != nil {
+		return err
+	}
+
+	elem := config.Element{Name: name, Type: config.ElementType(elemType)}
+	if err := editAttributes(&elem); err != nil {
+		return err
+	}
+	cfg.Elements = append(cfg.Elements, elem)
+	return nil
+}
+
+// editElement re-runs the attribute prompts for an existing element
+func editElement(cfg *config.Config) error {
+	index, err := chooseElement(cfg, "Which element would you like to edit?")
+	if err != nil {
+		return err
+	}
+	elem := cfg.Elements[index]
+	if err := editAttributes(&elem); err != nil {
+		return err
+	}
+	cfg.Elements[index] = elem
+	return nil
+}
+
+// moveElement moves an element to a new position in the prompt order
+func moveElement(cfg *config.Config) error {
+	from, err := chooseElement(cfg, "Which element would you like to move?")
+	if err != nil {
+		return err
+	}
+
+	positions := make([]string, len(cfg.Elements))
+	for i := range cfg.Elements {
+		positions[i] = fmt.Sprintf("%d", i+1)
+	}
+	choice, err := chooseOne(positions, "Which position should \""+cfg.Elements[from].Name+"\" move to?")
+	if err != nil {
+		return err
+	}
+	var to int
+	fmt.Sscanf(choice, "%d", &to)
+	to--
+
+	elem := cfg.Elements[from]
+	rest := append(cfg.Elements[:from:from], cfg.Elements[from+1:]...)
+	cfg.Elements = append(rest[:to:to], append([]config.Element{elem}, rest[to:]...)...)
+	return nil
+}
+
+// removeElement deletes an element after confirmation
+func removeElement(cfg *config.Config) error {
+	index, err := chooseElement(cfg, "Which element would you like to remove?")
+	if err != nil {
+		return err
+	}
+	confirmed, err := tui.Confirm("Remove \"" + cfg.Elements[index].Name + "\"?")
+	if err != nil || !confirmed {
+		return err
+	}
+	cfg.Elements = append(cfg.Elements[:index], cfg.Elements[index+1:]...)
+	return nil
+}
+
+// save validates the config and writes it to disk.
+// Returns false if the user should keep editing.
+func save(cfg *config.Config) (bool, error) {
+	prompt.ClearScreen()
+	if !config.ValidateConfig(cfg) {
+		fixIt, err := tui.Confirm("This configuration has problems. Keep editing?")
+		if err != nil {
+			return false, err
+		}
+		if fixIt {
+			return false, nil
+		}
+	}
+
+	if err := config.SaveConfig(cfg); err != nil {
+		return false, err
+	}
+	output.PrintToStderr("Wrote " + cfg.FilePath)
+	return true, nil
+}
+
+// chooseElement asks the user to pick one of the configured elements
+// and returns its index
+func chooseElement(cfg *config.Config, instructions string) (int, error) {
+	names := make([]string, len(cfg.Elements))
+	for i, elem := range cfg.Elements {
+		names[i] = elem.Name
+	}
+	name, err := chooseOne(names, instructions)
+	if err != nil {
+		return 0, err
+	}
+	for i, elem := range cfg.Elements {
+		if elem.Name == name {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("element not found: %s", name)
+}
+
+// askElementName prompts for a unique element name
+func askElementName(cfg *config.Config, current string) (string, error) {
+	for {
+		name, err := tui.Input("e.g. ticket-number", "What should this element be called?")
+		if err != nil {
+			return "", err
+		}
+		name = strings.TrimSpace(name)
+		if name == "" {
+			output.PrintWarningToStderr("This input is required.")
+			continue
+		}
+		if name != current && hasElement(cfg, name) {
+			output.PrintWarningToStderr("There is already an element named \"" + name + "\".")
+			continue
+		}
+		return name, nil
+	}
+}
+
+// hasElement returns true if an element with the given name exists
+func hasElement(cfg *config.Config, name string) bool {
+	for _, elem := range cfg.Elements {
+		if elem.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// chooseOne is a single-select tui.Choose that returns the chosen item
+func chooseOne(options []string, instructions string) (string, error) {
+	selected, err := tui.Choose(options, 1, instructions)
+	if err != nil {
+		return "", err
+	}
+	if len(selected) == 0 {
+		return "", tui.ErrAborted
+	}
+	return selected[0], nil
+}
@@ -2,7 +2,8 @@ package tui
 
 import (
 	"errors"
-	"os"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/help"
@@ -15,8 +16,27 @@ import (
 // ErrAborted is returned when the user cancels the selection
 var ErrAborted = errors.New("user aborted")
 
+// ChooseOptions configures ChooseWith
+type ChooseOptions struct {
+	Options      []string
+	Limit        int // 1 for a single selection, 0 or less for unlimited
+	Instructions string
+	Selected     []string // options that start out selected
+
+	// InSelectionOrder returns the selections in the order they were
+	// chosen, instead of the order of the options, and shows each
+	// one's rank next to it
+	InSelectionOrder bool
+}
+
 // Choose displays an interactive selection list and returns the selected items
 func Choose(options []string, limit int, instructions string) ([]string, error) {
+	return ChooseWith(ChooseOptions{Options: options, Limit: limit, Instructions: instructions})
+}
+
+// ChooseWith displays an interactive selection list and returns the selected items
+func ChooseWith(opts ChooseOptions) ([]string, error) {
+	options, limit, instructions := opts.Options, opts.Limit, opts.Instructions
 	if len(options) == 0 {
 		return nil, errors.New("no options provided")
 	}
@@ -70,6 +90,7 @@ func Choose(options []string, limit int, instructions string) ([]string, error)
 		header:           instructions,
 		items:            items,
 		limit:            limit,
+		showOrder:        opts.InSelectionOrder,
 		paginator:        p,
 		showHelp:         true,
 		help:             help.New(),
@@ -79,7 +100,9 @@ func Choose(options []string, limit int, instructions string) ([]string, error)
 		selectedItemStyle: lipgloss.NewStyle().Foreground(lipgloss.Color("212")),
 	}
 
-	tm, err := tea.NewProgram(m, tea.WithOutput(os.Stderr)).Run()
+	m = m.preselect(opts.Selected)
+
+	tm, err := newProgram(m).Run()
 	if err != nil {
 		return nil, err
 	}
@@ -89,14 +112,23 @@ func Choose(options []string, limit int, instructions string) ([]string, error)
 		return nil, ErrAborted
 	}
 
-	var selected []string
+	var selected []chooseItem
 	for _, item := range m.items {
 		if item.selected {
-			selected = append(selected, item.text)
+			selected = append(selected, item)
 		}
 	}
+	if opts.InSelectionOrder {
+		slices.SortStableFunc(selected, func(a, b chooseItem) int {
+			return a.order - b.order
+		})
+	}
 
-	return selected, nil
+	texts := make([]string, len(selected))
+	for i, item := range selected {
+		texts[i] = item.text
+	}
+	return texts, nil
 }
 
 type chooseItem struct {
@@ -148,6 +180,7 @@ type chooseModel struct {
 	submitted        bool
 	index            int
 	limit            int
+	showOrder        bool
 	numSelected      int
 	currentOrder     int
 	paginator        paginator.Model
@@ -300,6 +333,34 @@ func (m chooseModel) selectAll() chooseModel {
 	return m
 }
 
+// preselect selects the given options (just moving the cursor for a
+// single selection) and puts the cursor on the first of them
+func (m chooseModel) preselect(selected []string) chooseModel {
+	first := -1
+	// in the order they're given, so it's kept for InSelectionOrder
+	for _, text := range selected {
+		for i, item := range m.items {
+			if item.text != text {
+				continue
+			}
+			if first < 0 || i < first {
+				first = i
+			}
+			if m.limit > 1 && m.numSelected < m.limit && !m.items[i].selected {
+				m.items[i].selected = true
+				m.items[i].order = m.currentOrder
+				m.numSelected++
+				m.currentOrder++
+			}
+		}
+	}
+	if first >= 0 {
+		m.index = first
+		m.paginator.Page = first / m.height
+	}
+	return m
+}
+
 func (m chooseModel) deselectAll() chooseModel {
 	for i := range m.items {
 		m.items[i].selected = false
@@ -348,7 +409,9 @@ func (m chooseModel) renderItem(item chooseItem, isCursor bool) string {
 	}
 
 	// Render item text with appropriate style
-	if item.selected {
+	if item.selected && m.showOrder {
+		s.WriteString(m.selectedItemStyle.Render(m.selectedPrefix + m.rank(item) + " " + item.text))
+	} else if item.selected {
 		s.WriteString(m.selectedItemStyle.Render(m.selectedPrefix + item.text))
 	} else if isCursor {
 		s.WriteString(m.cursorStyle.Render(m.cursorPrefix + item.text))
@@ -359,6 +422,18 @@ func (m chooseModel) renderItem(item chooseItem, isCursor bool) string {
 	return s.String()
 }
 
+// rank returns where a selected item is in the order things were
+// selected, counting from 1
+func (m chooseModel) rank(item chooseItem) string {
+	rank := 1
+	for _, other := range m.items {
+		if other.selected && other.order < item.order {
+			rank++
+		}
+	}
+	return strconv.Itoa(rank)
+}
+
 // assembleParts combines header, items, and help into the final view
 func (m chooseModel) assembleParts(itemsView string) string {
 	var parts []string
@@ -1,8 +1,6 @@
 package tui
 
 import (
-	"os"
-
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
@@ -27,7 +25,7 @@ func Confirm(prompt string) (bool, error) {
 		unselectedStyle: lipgloss.NewStyle().Background(lipgloss.Color("235")).Foreground(lipgloss.Color("254")).Padding(0, 3),
 	}
 
-	tm, err := tea.NewProgram(m, tea.WithOutput(os.Stderr)).Run()
+	tm, err := newProgram(m).Run()
 	if err != nil {
 		return false, err
 	}
@@ -0,0 +1,126 @@
+package tui
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// DiffContext is what's being committed, shown alongside text prompts
+type DiffContext struct {
+	Summary string // one line per file, shown above the prompt
+	Patch   string // the full diff, shown in the pager
+}
+
+var (
+	// diffContext is shown by Input and Write when set
+	diffContext *DiffContext
+
+	// showDiffSummary is remembered between prompts
+	showDiffSummary bool
+
+	diffSummaryStyle = lipgloss.NewStyle().Faint(true)
+	pagerFooterStyle = lipgloss.NewStyle().Faint(true)
+)
+
+// SetDiffContext makes the staged changes available to Input and Write.
+// Pass nil to turn it off.
+func SetDiffContext(ctx *DiffContext) {
+	diffContext = ctx
+}
+
+// diffKeymap holds the keys for the diff summary and pager
+type diffKeymap struct {
+	ToggleSummary key.Binding
+	OpenPager     key.Binding
+	ClosePager    key.Binding
+}
+
+func diffDefaultKeymap() diffKeymap {
+	km := diffKeymap{
+		ToggleSummary: key.NewBinding(key.WithKeys("ctrl+s"), key.WithHelp("ctrl+s", "changes")),
+		OpenPager:     key.NewBinding(key.WithKeys("ctrl+o"), key.WithHelp("ctrl+o", "diff")),
+		ClosePager:    key.NewBinding(key.WithKeys("q", "esc", "ctrl+o"), key.WithHelp("q", "back")),
+	}
+	km.ToggleSummary.SetEnabled(diffContext != nil)
+	km.OpenPager.SetEnabled(diffContext != nil)
+	return km
+}
+
+// diffPanel adds the staged diff summary and pager to a prompt
+type diffPanel struct {
+	pager  viewport.Model
+	paging bool
+	width  int
+	height int
+}
+
+func newDiffPanel() diffPanel {
+	return diffPanel{width: 80, height: 24}
+}
+
+// update handles the panel's keys. It returns true if msg was
+// consumed and shouldn't be passed on to the prompt.
+func (p diffPanel) update(msg tea.Msg, keymap diffKeymap) (diffPanel, bool, tea.Cmd) {
+	if size, ok := msg.(tea.WindowSizeMsg); ok {
+		p.width, p.height = size.Width, size.Height
+		p.pager.Width, p.pager.Height = p.pagerSize()
+		return p, false, nil
+	}
+
+	if p.paging {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			switch {
+			case keyMsg.String() == "ctrl+c":
+				// leave aborting to the prompt
+				return p, false, nil
+			case key.Matches(keyMsg, keymap.ClosePager):
+				p.paging = false
+				return p, true, nil
+			}
+		}
+		var cmd tea.Cmd
+		p.pager, cmd = p.pager.Update(msg)
+		return p, true, cmd
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return p, false, nil
+	}
+	switch {
+	case key.Matches(keyMsg, keymap.ToggleSummary):
+		showDiffSummary = !showDiffSummary
+		return p, true, nil
+	case key.Matches(keyMsg, keymap.OpenPager):
+		p.pager = viewport.New(p.pagerSize())
+		p.pager.SetContent(diffContext.Patch)
+		p.paging = true
+		return p, true, nil
+	}
+	return p, false, nil
+}
+
+// pagerSize leaves a line for the footer
+func (p diffPanel) pagerSize() (int, int) {
+	return p.width, max(p.height-1, 1)
+}
+
+// view wraps the prompt's view with the summary, or replaces it with the pager
+func (p diffPanel) view(prompt string) string {
+	if p.paging {
+		footer := pagerFooterStyle.Render("↑/↓ pgup/pgdn: scroll • q: back to the prompt")
+		return lipgloss.JoinVertical(lipgloss.Left, p.pager.View(), footer)
+	}
+	if diffContext == nil || !showDiffSummary {
+		return prompt
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, diffSummaryStyle.Render(diffContext.Summary), "", prompt)
+}
+
+// ShowDiffSummary sets whether the summary starts out visible.
+// The user can still toggle it.
+func ShowDiffSummary(show bool) {
+	showDiffSummary = show
+}
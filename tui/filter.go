@@ -0,0 +1,340 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// FilterOptions configures Filter
+type FilterOptions struct {
+	Options      []string
+	Limit        int // 1 for a single selection, 0 or less for unlimited
+	Instructions string
+	Placeholder  string
+	Selected     []string // options that start out selected
+	Value        string   // pre-filled search
+	Error        string   // why the last answer was rejected, shown under the list
+	AllowNew     bool     // submitting text that matches nothing returns it in Query
+}
+
+// FilterResult is what was chosen in Filter
+type FilterResult struct {
+	Selected []string // the chosen options, in the order they're listed
+	Query    string   // what was typed, when it matched nothing and AllowNew
+}
+
+// Filter displays a list that's narrowed down by typing. Every word typed
+// has to appear in an option for it to be listed. Submitting when
+// nothing matches returns what was typed in Query, if AllowNew, along
+// with anything already selected.
+func Filter(opts FilterOptions) (FilterResult, error) {
+	m, err := newFilterModel(opts)
+	if err != nil {
+		return FilterResult{}, err
+	}
+
+	tm, err := newProgram(m).Run()
+	if err != nil {
+		return FilterResult{}, err
+	}
+
+	m = tm.(filterModel)
+	if !m.submitted {
+		return FilterResult{}, ErrAborted
+	}
+
+	return m.result(), nil
+}
+
+// newFilterModel sets up the list for opts, with the options selected
+func newFilterModel(opts FilterOptions) (filterModel, error) {
+	if len(opts.Options) == 0 && !opts.AllowNew {
+		return filterModel{}, errors.New("no options provided")
+	}
+
+	// a multiple selection can grow with what's typed, so "no limit"
+	// can't be worked out from how many options are listed
+	limit, noLimit := opts.Limit, opts.Limit <= 0
+
+	ti := textinput.New()
+	ti.Placeholder = opts.Placeholder
+	ti.SetValue(opts.Value)
+	ti.Focus()
+	ti.CharLimit = 0 // No limit
+	ti.Width = 60
+
+	km := filterDefaultKeymap()
+	km.Toggle.SetEnabled(noLimit || limit > 1)
+
+	items := make([]chooseItem, len(opts.Options))
+	for i, option := range opts.Options {
+		items[i] = chooseItem{text: option}
+	}
+
+	m := filterModel{
+		textinput:         ti,
+		header:            opts.Instructions,
+		problem:           opts.Error,
+		items:             items,
+		height:            min(10, len(items)),
+		limit:             limit,
+		noLimit:           noLimit,
+		allowNew:          opts.AllowNew,
+		showHelp:          true,
+		help:              help.New(),
+		keymap:            km,
+		cursorStyle:       lipgloss.NewStyle().Foreground(lipgloss.Color("212")),
+		selectedItemStyle: lipgloss.NewStyle().Foreground(lipgloss.Color("212")),
+	}
+	return m.filter().preselect(opts.Selected), nil
+}
+
+// result returns what was chosen
+func (m filterModel) result() FilterResult {
+	var result FilterResult
+	for _, item := range m.items {
+		if item.selected {
+			result.Selected = append(result.Selected, item.text)
+		}
+	}
+	if m.allowNew && len(m.matches) == 0 {
+		result.Query = strings.TrimSpace(m.textinput.Value())
+	}
+	return result
+}
+
+// filterMatches returns true if every word of query is in text, ignoring case
+func filterMatches(text, query string) bool {
+	text = strings.ToLower(text)
+	for _, word := range strings.Fields(strings.ToLower(query)) {
+		if !strings.Contains(text, word) {
+			return false
+		}
+	}
+	return true
+}
+
+type filterKeymap struct {
+	Down, Up, Toggle    key.Binding
+	Abort, Quit, Submit key.Binding
+}
+
+func (k filterKeymap) FullHelp() [][]key.Binding { return nil }
+func (k filterKeymap) ShortHelp() []key.Binding {
+	return []key.Binding{
+		k.Toggle,
+		key.NewBinding(key.WithKeys("↑", "↓"), key.WithHelp("↑↓", "navigate")),
+		k.Submit,
+	}
+}
+
+func filterDefaultKeymap() filterKeymap {
+	// letters are for typing, so only keys that don't print move around
+	return filterKeymap{
+		Down:   key.NewBinding(key.WithKeys("down", "ctrl+n")),
+		Up:     key.NewBinding(key.WithKeys("up", "ctrl+p")),
+		Toggle: key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "toggle"), key.WithDisabled()),
+		Abort:  key.NewBinding(key.WithKeys("ctrl+c"), key.WithHelp("ctrl+c", "abort")),
+		Quit:   key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "quit")),
+		Submit: key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "submit")),
+	}
+}
+
+type filterModel struct {
+	textinput         textinput.Model
+	header            string
+	problem           string
+	items             []chooseItem
+	matches           []int // indexes of the items that match, in order
+	index             int   // the cursor, in matches
+	offset            int   // the first match shown
+	height            int
+	limit             int
+	noLimit           bool
+	allowNew          bool
+	numSelected       int
+	quitting          bool
+	submitted         bool
+	showHelp          bool
+	help              help.Model
+	keymap            filterKeymap
+	cursorStyle       lipgloss.Style
+	headerStyle       lipgloss.Style
+	selectedItemStyle lipgloss.Style
+}
+
+func (m filterModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m filterModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		km := m.keymap
+		switch {
+		case key.Matches(msg, km.Quit), key.Matches(msg, km.Abort):
+			m.quitting = true
+			return m, tea.Quit
+		case key.Matches(msg, km.Down):
+			return m.moveCursor(1), nil
+		case key.Matches(msg, km.Up):
+			return m.moveCursor(-1), nil
+		case key.Matches(msg, km.Toggle):
+			return m.handleToggle(), nil
+		case key.Matches(msg, km.Submit):
+			return m.handleSubmit()
+		}
+	}
+
+	var cmd tea.Cmd
+	query := m.textinput.Value()
+	m.textinput, cmd = m.textinput.Update(msg)
+	if m.textinput.Value() != query {
+		m = m.filter()
+	}
+	return m, cmd
+}
+
+// filter lists the items that match what's been typed
+func (m filterModel) filter() filterModel {
+	m.matches = m.matches[:0]
+	for i, item := range m.items {
+		if filterMatches(item.text, m.textinput.Value()) {
+			m.matches = append(m.matches, i)
+		}
+	}
+	m.index, m.offset = 0, 0
+	return m
+}
+
+// moveCursor moves the cursor by delta matches, wrapping around the ends
+func (m filterModel) moveCursor(delta int) filterModel {
+	if len(m.matches) == 0 {
+		return m
+	}
+	m.index = (m.index + delta + len(m.matches)) % len(m.matches)
+	if m.index < m.offset {
+		m.offset = m.index
+	} else if m.index >= m.offset+m.height {
+		m.offset = m.index - m.height + 1
+	}
+	return m
+}
+
+func (m filterModel) handleToggle() filterModel {
+	if !m.multiple() || len(m.matches) == 0 {
+		return m
+	}
+	item := &m.items[m.matches[m.index]]
+	if item.selected {
+		item.selected = false
+		m.numSelected--
+	} else if m.canSelectMore() {
+		item.selected = true
+		m.numSelected++
+	}
+	return m.moveCursor(1)
+}
+
+// multiple returns true if more than one option can be selected
+func (m filterModel) multiple() bool {
+	return m.noLimit || m.limit > 1
+}
+
+// canSelectMore returns true if the limit hasn't been reached
+func (m filterModel) canSelectMore() bool {
+	return m.noLimit || m.numSelected < m.limit
+}
+
+func (m filterModel) handleSubmit() (tea.Model, tea.Cmd) {
+	if m.numSelected < 1 && len(m.matches) == 0 && !m.allowNew {
+		return m, nil
+	}
+	// If nothing is selected, select the cursor item, if there is one
+	if m.numSelected < 1 && len(m.matches) > 0 {
+		m.items[m.matches[m.index]].selected = true
+	}
+	m.quitting = true
+	m.submitted = true
+	return m, tea.Quit
+}
+
+// preselect selects the given options (just moving the cursor for a
+// single selection) and puts the cursor on the first of them
+func (m filterModel) preselect(selected []string) filterModel {
+	first := -1
+	for i, match := range m.matches {
+		item := &m.items[match]
+		for _, text := range selected {
+			if item.text != text {
+				continue
+			}
+			if first < 0 {
+				first = i
+			}
+			if m.multiple() && !item.selected && m.canSelectMore() {
+				item.selected = true
+				m.numSelected++
+			}
+		}
+	}
+	if first >= 0 {
+		m = m.moveCursor(first)
+	}
+	return m
+}
+
+func (m filterModel) View() string {
+	if m.quitting {
+		return ""
+	}
+
+	// For single select, we don't need prefixes
+	selectedPrefix, unselectedPrefix := "", ""
+	if m.multiple() {
+		selectedPrefix, unselectedPrefix = "✓ ", "• "
+	}
+
+	var lines []string
+	end := min(m.offset+m.height, len(m.matches))
+	for i := m.offset; i < end; i++ {
+		item := m.items[m.matches[i]]
+		cursor := "  "
+		if i == m.index {
+			cursor = m.cursorStyle.Render("> ")
+		}
+		switch {
+		case item.selected:
+			lines = append(lines, cursor+m.selectedItemStyle.Render(selectedPrefix+item.text))
+		case i == m.index:
+			lines = append(lines, cursor+m.cursorStyle.Render(unselectedPrefix+item.text))
+		default:
+			lines = append(lines, cursor+unselectedPrefix+item.text)
+		}
+	}
+	if len(m.matches) == 0 {
+		if m.allowNew {
+			lines = append(lines, "  (no matches, enter uses what you typed)")
+		} else {
+			lines = append(lines, "  (no matches)")
+		}
+	}
+
+	var parts []string
+	if m.header != "" {
+		parts = append(parts, m.headerStyle.Render(m.header))
+	}
+	parts = append(parts, m.textinput.View(), strings.Join(lines, "\n"))
+	if m.problem != "" {
+		parts = append(parts, problemStyle.Render(m.problem))
+	}
+	if m.showHelp {
+		parts = append(parts, "", m.help.View(m.keymap))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, parts...)
+}
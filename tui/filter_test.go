@@ -0,0 +1,85 @@
+package tui
+
+import (
+	"slices"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// submitFilter types query into m and submits it
+func submitFilter(t *testing.T, m filterModel, query string) FilterResult {
+	t.Helper()
+	if query != "" {
+		tm, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(query)})
+		m = tm.(filterModel)
+	}
+	tm, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = tm.(filterModel)
+	if !m.submitted {
+		t.Fatalf("submitting %q was ignored", query)
+	}
+	return m.result()
+}
+
+func TestFilter_NoLimitWithFewOptions(t *testing.T) {
+	// a multiple ticket picker with one listed ticket, where two more
+	// are typed in one after the other
+	opts := FilterOptions{
+		Options:  []string{"PROJ-1 Fix login"},
+		Limit:    -1,
+		AllowNew: true,
+	}
+	m, err := newFilterModel(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !m.keymap.Toggle.Enabled() {
+		t.Fatal("toggle should be enabled without a limit")
+	}
+	tm, _ := m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	result := submitFilter(t, tm.(filterModel), "PROJ-2")
+	if want := []string{"PROJ-1 Fix login"}; !slices.Equal(result.Selected, want) || result.Query != "PROJ-2" {
+		t.Fatalf("first answer = %+v, want %v and PROJ-2", result, want)
+	}
+
+	opts.Options = append(opts.Options, result.Query)
+	opts.Selected = append(result.Selected, result.Query)
+	m, err = newFilterModel(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result = submitFilter(t, m, "PROJ-3")
+	if want := []string{"PROJ-1 Fix login", "PROJ-2"}; !slices.Equal(result.Selected, want) || result.Query != "PROJ-3" {
+		t.Fatalf("second answer = %+v, want %v and PROJ-3", result, want)
+	}
+
+	opts.Options = append(opts.Options, result.Query)
+	opts.Selected = append(result.Selected, result.Query)
+	m, err = newFilterModel(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result = submitFilter(t, m, "")
+	if want := []string{"PROJ-1 Fix login", "PROJ-2", "PROJ-3"}; !slices.Equal(result.Selected, want) || result.Query != "" {
+		t.Fatalf("last answer = %+v, want %v", result, want)
+	}
+}
+
+func TestFilter_SingleSelection(t *testing.T) {
+	m, err := newFilterModel(FilterOptions{
+		Options:  []string{"one", "two"},
+		Limit:    1,
+		Selected: []string{"two"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.keymap.Toggle.Enabled() {
+		t.Fatal("toggle should be disabled for a single selection")
+	}
+	result := submitFilter(t, m, "")
+	if want := []string{"two"}; !slices.Equal(result.Selected, want) {
+		t.Fatalf("Selected = %v, want %v", result.Selected, want)
+	}
+}
@@ -1,8 +1,6 @@
 package tui
 
 import (
-	"os"
-
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
@@ -10,24 +8,53 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
+// InputOptions configures InputWith
+type InputOptions struct {
+	Placeholder  string
+	Instructions string
+	Value        string // pre-filled text
+	Error        string // why the last value was rejected, shown under the input
+
+	// Suggestions complete what's been typed: tab accepts the one
+	// shown, up and down cycle through the others
+	Suggestions []string
+}
+
 // Input displays an interactive text input and returns the entered text
 func Input(placeholder string, instructions string) (string, error) {
+	return InputWith(InputOptions{Placeholder: placeholder, Instructions: instructions})
+}
+
+// InputWith displays an interactive text input and returns the entered text
+func InputWith(opts InputOptions) (string, error) {
+	placeholder, instructions := opts.Placeholder, opts.Instructions
+
 	ti := textinput.New()
 	ti.Placeholder = placeholder
+	ti.SetValue(opts.Value)
 	ti.Focus()
 	ti.CharLimit = 0 // No limit
 	ti.Width = 60
+	if len(opts.Suggestions) > 0 {
+		ti.ShowSuggestions = true
+		ti.SetSuggestions(opts.Suggestions)
+	}
+
+	km := inputDefaultKeymap()
+	km.Complete.SetEnabled(len(opts.Suggestions) > 0)
 
 	m := inputModel{
 		textinput: ti,
 		autoWidth: true,
 		header:    instructions,
+		problem:   opts.Error,
 		showHelp:  true,
 		help:      help.New(),
-		keymap:    inputDefaultKeymap(),
+		keymap:    km,
+		diff:      newDiffPanel(),
 	}
 
-	tm, err := tea.NewProgram(m, tea.WithOutput(os.Stderr)).Run()
+	tm, err := newProgram(m).Run()
 	if err != nil {
 		return "", err
 	}
@@ -40,22 +67,29 @@ func Input(placeholder string, instructions string) (string, error) {
 	return m.textinput.Value(), nil
 }
 
+// problemStyle is used for explaining why input was rejected
+var problemStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+
 type inputKeymap struct {
-	Submit key.Binding
-	Abort  key.Binding
-	Quit   key.Binding
+	diffKeymap
+	Complete key.Binding // handled by textinput, listed for the help
+	Submit   key.Binding
+	Abort    key.Binding
+	Quit     key.Binding
 }
 
 func (k inputKeymap) FullHelp() [][]key.Binding { return nil }
 func (k inputKeymap) ShortHelp() []key.Binding {
-	return []key.Binding{k.Submit}
+	return []key.Binding{k.Complete, k.Submit, k.ToggleSummary, k.OpenPager}
 }
 
 func inputDefaultKeymap() inputKeymap {
 	return inputKeymap{
-		Submit: key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "submit")),
-		Abort:  key.NewBinding(key.WithKeys("ctrl+c"), key.WithHelp("ctrl+c", "abort")),
-		Quit:   key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "quit")),
+		diffKeymap: diffDefaultKeymap(),
+		Complete:   key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab/↑↓", "complete"), key.WithDisabled()),
+		Submit:     key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "submit")),
+		Abort:      key.NewBinding(key.WithKeys("ctrl+c"), key.WithHelp("ctrl+c", "abort")),
+		Quit:       key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "quit")),
 	}
 }
 
@@ -64,11 +98,13 @@ type inputModel struct {
 	autoWidth   bool
 	header      string
 	headerStyle lipgloss.Style
+	problem     string
 	quitting    bool
 	submitted   bool
 	showHelp    bool
 	help        help.Model
 	keymap      inputKeymap
+	diff        diffPanel
 }
 
 func (m inputModel) Init() tea.Cmd {
@@ -76,6 +112,12 @@ func (m inputModel) Init() tea.Cmd {
 }
 
 func (m inputModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var handled bool
+	var cmd tea.Cmd
+	if m.diff, handled, cmd = m.diff.update(msg, m.keymap.diffKeymap); handled {
+		return m, cmd
+	}
+
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		if m.autoWidth {
@@ -96,7 +138,6 @@ func (m inputModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	}
 
-	var cmd tea.Cmd
 	m.textinput, cmd = m.textinput.Update(msg)
 	return m, cmd
 }
@@ -111,9 +152,12 @@ func (m inputModel) View() string {
 		parts = append(parts, m.headerStyle.Render(m.header))
 	}
 	parts = append(parts, m.textinput.View())
+	if m.problem != "" {
+		parts = append(parts, problemStyle.Render(m.problem))
+	}
 	if m.showHelp {
 		parts = append(parts, "", m.help.View(m.keymap))
 	}
 
-	return lipgloss.JoinVertical(lipgloss.Left, parts...)
+	return m.diff.view(lipgloss.JoinVertical(lipgloss.Left, parts...))
 }
@@ -0,0 +1,27 @@
+package tui
+
+import (
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// newProgram creates a program that draws on stderr.
+// When stdin isn't a terminal (e.g. when git runs us as a hook)
+// keyboard input is read from the controlling terminal instead.
+func newProgram(m tea.Model) *tea.Program {
+	options := []tea.ProgramOption{tea.WithOutput(os.Stderr)}
+	if !isTerminal(os.Stdin) {
+		options = append(options, tea.WithInputTTY())
+	}
+	return tea.NewProgram(m, options...)
+}
+
+// isTerminal returns true if f is a character device
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -1,8 +1,6 @@
 package tui
 
 import (
-	"os"
-
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textarea"
@@ -10,19 +8,32 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
+// WriteOptions configures WriteWith
+type WriteOptions struct {
+	Placeholder  string
+	Instructions string
+	Value        *string // pre-filled text, if not nil
+	Error        string  // why the last text was rejected, shown under the textarea
+}
+
 // Write displays an interactive multiline text input and returns the entered text
 // If initialContent is not nil, the textarea will be pre-filled with that content
 func Write(placeholder string, instructions string, initialContent *string) (string, error) {
+	return WriteWith(WriteOptions{Placeholder: placeholder, Instructions: instructions, Value: initialContent})
+}
+
+// WriteWith displays an interactive multiline text input and returns the entered text
+func WriteWith(opts WriteOptions) (string, error) {
 	ta := textarea.New()
-	ta.Placeholder = placeholder
+	ta.Placeholder = opts.Placeholder
 	ta.Focus()
 	ta.CharLimit = 0 // No limit
 	ta.SetWidth(80)
 	ta.SetHeight(10)
 
 	// Pre-fill with initial content if provided
-	if initialContent != nil {
-		ta.SetValue(*initialContent)
+	if opts.Value != nil {
+		ta.SetValue(*opts.Value)
 	}
 
 	km := writeDefaultKeymap()
@@ -31,13 +42,15 @@ func Write(placeholder string, instructions string, initialContent *string) (str
 	m := writeModel{
 		textarea:  ta,
 		autoWidth: true,
-		header:    instructions,
+		header:    opts.Instructions,
+		problem:   opts.Error,
 		showHelp:  true,
 		help:      help.New(),
 		keymap:    km,
+		diff:      newDiffPanel(),
 	}
 
-	tm, err := tea.NewProgram(m, tea.WithOutput(os.Stderr)).Run()
+	tm, err := newProgram(m).Run()
 	if err != nil {
 		return "", err
 	}
@@ -52,6 +65,7 @@ func Write(placeholder string, instructions string, initialContent *string) (str
 
 type writeKeymap struct {
 	textarea.KeyMap
+	diffKeymap
 	Submit key.Binding
 	Abort  key.Binding
 	Quit   key.Binding
@@ -59,7 +73,7 @@ type writeKeymap struct {
 
 func (k writeKeymap) FullHelp() [][]key.Binding { return nil }
 func (k writeKeymap) ShortHelp() []key.Binding {
-	return []key.Binding{k.InsertNewline, k.Submit}
+	return []key.Binding{k.InsertNewline, k.Submit, k.ToggleSummary, k.OpenPager}
 }
 
 func writeDefaultKeymap() writeKeymap {
@@ -70,10 +84,11 @@ func writeDefaultKeymap() writeKeymap {
 		key.WithHelp("enter", "new line"),
 	)
 	return writeKeymap{
-		KeyMap: km,
-		Submit: key.NewBinding(key.WithKeys("ctrl+d"), key.WithHelp("ctrl+d", "submit")),
-		Abort:  key.NewBinding(key.WithKeys("ctrl+c"), key.WithHelp("ctrl+c", "abort")),
-		Quit:   key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "quit")),
+		KeyMap:     km,
+		diffKeymap: diffDefaultKeymap(),
+		Submit:     key.NewBinding(key.WithKeys("ctrl+d"), key.WithHelp("ctrl+d", "submit")),
+		Abort:      key.NewBinding(key.WithKeys("ctrl+c"), key.WithHelp("ctrl+c", "abort")),
+		Quit:       key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "quit")),
 	}
 }
 
@@ -82,11 +97,13 @@ type writeModel struct {
 	autoWidth   bool
 	header      string
 	headerStyle lipgloss.Style
+	problem     string
 	quitting    bool
 	submitted   bool
 	showHelp    bool
 	help        help.Model
 	keymap      writeKeymap
+	diff        diffPanel
 }
 
 func (m writeModel) Init() tea.Cmd {
@@ -94,6 +111,12 @@ func (m writeModel) Init() tea.Cmd {
 }
 
 func (m writeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var handled bool
+	var cmd tea.Cmd
+	if m.diff, handled, cmd = m.diff.update(msg, m.keymap.diffKeymap); handled {
+		return m, cmd
+	}
+
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		if m.autoWidth {
@@ -114,7 +137,6 @@ func (m writeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	}
 
-	var cmd tea.Cmd
 	m.textarea, cmd = m.textarea.Update(msg)
 	return m, cmd
 }
@@ -129,9 +151,12 @@ func (m writeModel) View() string {
 		parts = append(parts, m.headerStyle.Render(m.header))
 	}
 	parts = append(parts, m.textarea.View())
+	if m.problem != "" {
+		parts = append(parts, problemStyle.Render(m.problem))
+	}
 	if m.showHelp {
 		parts = append(parts, "", m.help.View(m.keymap))
 	}
 
-	return lipgloss.JoinVertical(lipgloss.Left, parts...)
+	return m.diff.view(lipgloss.JoinVertical(lipgloss.Left, parts...))
 }